@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/albenik/twirp-doc-gen/internal/plugin"
+)
+
+const usage = `Usage: twirp-doc [-o dir] [-opt options] descriptor_set [file.proto ...]
+
+Generates the documentation from the binary or JSON FileDescriptorSet built by
+"buf build -o" or "protoc --include_source_info --descriptor_set_out", "-" reads it from stdin.
+Only the given files are documented, all the files of the set by default.
+
+`
+
+func main() {
+	flags := flag.NewFlagSet("twirp-doc", flag.ExitOnError)
+	out := flags.String("o", ".", "output directory")
+	opt := flags.String("opt", "", "comma separated plugin options, e.g. paths=source_relative,format=markdown+openapi")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(os.Args[1:])
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2) //nolint:gomnd
+	}
+
+	if err := run(flags.Arg(0), flags.Args()[1:], *opt, *out); err != nil {
+		fmt.Fprintf(os.Stderr, "twirp-doc: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(source string, files []string, opt, out string) error {
+	data, err := readSource(source)
+	if err != nil {
+		return err
+	}
+
+	set, err := plugin.ParseDescriptorSet(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", source, err)
+	}
+
+	req, err := plugin.NewRequest(set, files, opt)
+	if err != nil {
+		return err
+	}
+
+	resp, err := plugin.New().Execute(req)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range resp.File {
+		path := filepath.Join(out, filepath.FromSlash(f.GetName()))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil { //nolint:gomnd
+			return err
+		}
+		if err := os.WriteFile(path, []byte(f.GetContent()), 0o644); err != nil { //nolint:gomnd,gosec
+			return err
+		}
+	}
+
+	return nil
+}
+
+func readSource(source string) ([]byte, error) {
+	if source == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(source)
+}
@@ -0,0 +1,207 @@
+package doc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/albenik/twirp-doc-gen/twirpdoc"
+)
+
+// fieldExample returns the value of the (twirp_doc.example) field option.
+func fieldExample(fd protoreflect.FieldDescriptor) (string, bool) {
+	opts := fd.Options()
+	if !proto.HasExtension(opts, twirpdoc.E_Example) {
+		return "", false
+	}
+	return proto.GetExtension(opts, twirpdoc.E_Example).(string), true //nolint:forcetypeassert
+}
+
+// messageExamples returns the values of the (twirp_doc.examples) message option.
+func messageExamples(md protoreflect.MessageDescriptor) []string {
+	return proto.GetExtension(md.Options(), twirpdoc.E_Examples).([]string) //nolint:forcetypeassert
+}
+
+// isExampleEnumValue reports whether the enum value is marked by the (twirp_doc.example_value) option.
+func isExampleEnumValue(ev protoreflect.EnumValueDescriptor) bool {
+	return proto.GetExtension(ev.Options(), twirpdoc.E_ExampleValue).(bool) //nolint:forcetypeassert
+}
+
+const (
+	// internalDirective marks the element as internal in its leading or trailing comment.
+	internalDirective = "@internal"
+	// errorDirective declares the Twirp error of the method in its leading comment,
+	// e.g. `@error not_found "the user does not exist"`.
+	errorDirective = "@error"
+	// metaDirective declares the meta key of the method's Twirp error in its leading comment,
+	// e.g. `@meta invalid_argument argument "the name of the invalid field"`.
+	metaDirective = "@meta"
+)
+
+// isInternal reports whether the service, method, field or enum value is marked by the internal option
+// or the @internal comment directive.
+func isInternal(desc protoreflect.Descriptor) bool {
+	var ext protoreflect.ExtensionType
+	switch desc.(type) {
+	case protoreflect.ServiceDescriptor:
+		ext = twirpdoc.E_InternalService
+	case protoreflect.MethodDescriptor:
+		ext = twirpdoc.E_InternalMethod
+	case protoreflect.FieldDescriptor:
+		ext = twirpdoc.E_InternalField
+	case protoreflect.EnumValueDescriptor:
+		ext = twirpdoc.E_InternalValue
+	default:
+		return false
+	}
+
+	if proto.GetExtension(desc.Options(), ext).(bool) { //nolint:forcetypeassert
+		return true
+	}
+
+	loc := desc.ParentFile().SourceLocations().ByDescriptor(desc)
+	return hasInternalDirective(loc.LeadingComments) || hasInternalDirective(loc.TrailingComments)
+}
+
+func hasInternalDirective(comment string) bool {
+	for _, l := range strings.Split(comment, "\n") {
+		if name, _ := splitField(l); name == internalDirective {
+			return true
+		}
+	}
+	return false
+}
+
+// isDeprecated reports whether the element is marked by the deprecated option.
+func isDeprecated(desc protoreflect.Descriptor) bool {
+	opts, ok := desc.Options().(interface{ GetDeprecated() bool })
+	return ok && opts.GetDeprecated()
+}
+
+// methodErrors returns the Twirp errors declared by the (twirp_doc.errors) method option
+// and the @error comment directives.
+func methodErrors(method protoreflect.MethodDescriptor) ([]*ErrorSpec, error) {
+	declared := proto.GetExtension(method.Options(), twirpdoc.E_Errors).([]string) //nolint:forcetypeassert
+
+	errs := make([]*ErrorSpec, 0, len(declared))
+	for _, d := range declared {
+		code, desc, ok := splitPair(d, ":")
+		if !ok {
+			return nil, fmt.Errorf("method %s: invalid error %q, expected \"code: description\"", method.FullName(), d)
+		}
+		e, err := newMethodError(method, code, desc)
+		if err != nil {
+			return nil, err
+		}
+		errs = append(errs, e)
+	}
+
+	loc := method.ParentFile().SourceLocations().ByDescriptor(method)
+	for _, args := range directiveArgs(loc.LeadingComments, errorDirective) {
+		code, desc := splitField(args)
+		if code == "" {
+			return nil, fmt.Errorf("method %s: invalid %s directive without the error code, expected \"code description\"",
+				method.FullName(), errorDirective)
+		}
+		e, err := newMethodError(method, code, unquote(desc))
+		if err != nil {
+			return nil, err
+		}
+		errs = append(errs, e)
+	}
+
+	for _, d := range proto.GetExtension(method.Options(), twirpdoc.E_ErrorMeta).([]string) { //nolint:forcetypeassert
+		key, desc, ok := splitPair(d, ":")
+		code, key, dot := splitPair(key, ".")
+		if !ok || !dot {
+			return nil, fmt.Errorf("method %s: invalid error meta %q, expected \"code.key: description\"",
+				method.FullName(), d)
+		}
+		if err := addErrorMeta(method, errs, code, key, desc); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, args := range directiveArgs(loc.LeadingComments, metaDirective) {
+		code, rest := splitField(args)
+		key, desc := splitField(rest)
+		if key == "" {
+			return nil, fmt.Errorf("method %s: invalid %s directive %q, expected \"code key description\"",
+				method.FullName(), metaDirective, args)
+		}
+		if err := addErrorMeta(method, errs, code, key, unquote(desc)); err != nil {
+			return nil, err
+		}
+	}
+
+	return errs, nil
+}
+
+// addErrorMeta appends the meta key to the declared method error with the code.
+func addErrorMeta(method protoreflect.MethodDescriptor, errs []*ErrorSpec, code, key, desc string) error {
+	for _, e := range errs {
+		if e.Code == code {
+			e.Meta = append(e.Meta, &ErrorMeta{Key: key, Description: desc})
+			return nil
+		}
+	}
+	return fmt.Errorf("method %s: meta key %q of undeclared Twirp error %q", method.FullName(), key, code)
+}
+
+// directiveArgs returns the arguments of the directive lines in the comment, the lines are recognized
+// the same way isDirective does. The directive without arguments has the empty ones.
+func directiveArgs(comment, directive string) []string {
+	var args []string
+	for _, l := range strings.Split(comment, "\n") {
+		if name, rest := splitField(l); name == directive {
+			args = append(args, rest)
+		}
+	}
+	return args
+}
+
+// unquote returns the unquoted string if s is the quoted Go string literal or s as is.
+func unquote(s string) string {
+	if u, err := strconv.Unquote(s); err == nil {
+		return u
+	}
+	return s
+}
+
+func newMethodError(method protoreflect.MethodDescriptor, code, desc string) (*ErrorSpec, error) {
+	c := twirpErrorCodeByName(code)
+	if c == nil {
+		return nil, fmt.Errorf("method %s: unknown Twirp error code %q", method.FullName(), code)
+	}
+	return &ErrorSpec{Code: c.Code, HTTPStatus: c.HTTPStatus, Description: desc}, nil
+}
+
+// isDirective reports whether the comment line is the generator directive.
+func isDirective(l string) bool {
+	name, _ := splitField(l)
+	return name == internalDirective || name == errorDirective || name == metaDirective
+}
+
+// splitField splits s around the first run of white space, both parts are trimmed.
+func splitField(s string) (string, string) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, unicode.IsSpace)
+	if i < 0 {
+		return s, ""
+	}
+	return s[:i], strings.TrimSpace(s[i:])
+}
+
+// splitPair splits s around the first separator, both parts are trimmed.
+func splitPair(s, sep string) (string, string, bool) {
+	s = strings.TrimSpace(s)
+	i := strings.Index(s, sep)
+	if i < 0 {
+		return s, "", false
+	}
+	return strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+len(sep):]), true
+}
@@ -0,0 +1,147 @@
+package doc
+
+import (
+	"regexp"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+
+	md "github.com/albenik/twirp-doc-gen/internal/markdown"
+)
+
+var (
+	// commentRefRx matches the `[Label][pkg.Model]` and `[pkg.Model][]` references to the models.
+	commentRefRx = regexp.MustCompile(`\[([^\[\]]+)\]\[([^\[\]]*)\]`)
+	// codeFenceRx matches the opening and closing lines of the fenced code blocks.
+	codeFenceRx = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})")
+)
+
+// commentsBlock returns the leading and trailing comments of the element. The detached comments are rendered as notes
+// above them if enabled.
+func (g *Generator) commentsBlock(c *Comments) md.Block {
+	var blocks []md.Block
+
+	if g.opts.DetachedComments {
+		for _, d := range c.Detached {
+			if desc := g.descriptionBlock(protogen.Comments(d)); desc != nil {
+				blocks = append(blocks, md.Quote(md.P(md.TB("Note:")), desc))
+			}
+		}
+	}
+
+	for _, s := range []string{c.Leading, c.Trailing} {
+		if desc := g.descriptionBlock(protogen.Comments(s)); desc != nil {
+			blocks = append(blocks, desc)
+		}
+	}
+
+	if len(blocks) == 0 {
+		return nil
+	}
+	return md.G(blocks...)
+}
+
+// commentsCell returns the leading and trailing comments of the element fitting the single table cell
+// or nil if the element has no comments.
+func (g *Generator) commentsCell(c *Comments) md.Block {
+	leading := g.descriptionCellText(protogen.Comments(c.Leading))
+	trailing := g.descriptionCellText(protogen.Comments(c.Trailing))
+
+	switch {
+	case leading != nil && trailing != nil:
+		return md.G(md.CellP(leading), md.CellP(trailing))
+	case leading != nil:
+		return leading
+	case trailing != nil:
+		return trailing
+	}
+	return nil
+}
+
+// commentsText returns the leading and trailing comments of the element as a plain text.
+func commentsText(c *Comments) string {
+	return strings.TrimSpace(commentText(protogen.Comments(c.Leading)) + "\n\n" + commentText(protogen.Comments(c.Trailing)))
+}
+
+// withoutDirectives returns the comment without the generator directive lines.
+func withoutDirectives(c protogen.Comments) protogen.Comments {
+	lines := strings.Split(string(c), "\n")
+
+	kept := lines[:0]
+	for _, l := range lines {
+		if !isDirective(l) {
+			kept = append(kept, l)
+		}
+	}
+
+	s := strings.Join(kept, "\n")
+	if strings.TrimSpace(s) == "" {
+		return ""
+	}
+	return protogen.Comments(s)
+}
+
+// commentLines returns the lines of the comment without the single leading space put after the comment slashes,
+// the deeper indentation is kept as is.
+func commentLines(c protogen.Comments) []string {
+	lines := strings.Split(strings.TrimRight(string(c), "\n"), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimPrefix(l, " ")
+	}
+	return lines
+}
+
+// markdownComment returns the comment as CommonMark text with the model references resolved outside
+// of the code blocks.
+func (g *Generator) markdownComment(c protogen.Comments) string {
+	lines := commentLines(c)
+
+	var fence string
+	for i, l := range lines {
+		if m := codeFenceRx.FindStringSubmatch(l); m != nil {
+			switch {
+			case fence == "":
+				fence = m[1]
+			case m[1][0] == fence[0] && len(m[1]) >= len(fence) && strings.TrimSpace(l) == m[1]:
+				fence = ""
+			}
+			continue
+		}
+		if fence == "" && !isIndentedCode(l) {
+			lines[i] = g.resolveReferences(l)
+		}
+	}
+	return strings.TrimRight(strings.TrimLeft(strings.Join(lines, "\n"), "\n"), " \n")
+}
+
+// markdownCommentCell returns the comment as CommonMark text fitting the single table cell.
+func (g *Generator) markdownCommentCell(c protogen.Comments) string {
+	paragraphs := strings.Split(strings.TrimSpace(string(c)), "\n\n")
+	for i, p := range paragraphs {
+		paragraphs[i] = g.resolveReferences(strings.Join(strings.Fields(p), " "))
+	}
+	return strings.Join(paragraphs, "<br/>")
+}
+
+// resolveReferences replaces the model references with the links to the models of the rendered document.
+// Other references are kept as is, they may be the CommonMark reference links defined in the comment.
+func (g *Generator) resolveReferences(s string) string {
+	return commentRefRx.ReplaceAllStringFunc(s, func(ref string) string {
+		m := commentRefRx.FindStringSubmatch(ref)
+		label, target := m[1], m[2]
+		if target == "" {
+			target = label
+		}
+
+		if g.document != nil {
+			if anchor, ok := g.document.modelAnchor(strings.TrimPrefix(target, ".")); ok {
+				return "[" + label + "](" + anchor + ")"
+			}
+		}
+		return ref
+	})
+}
+
+func isIndentedCode(l string) bool {
+	return strings.HasPrefix(l, "    ") || strings.HasPrefix(l, "\t")
+}
@@ -1,14 +1,21 @@
 package doc
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
+	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/types/dynamicpb"
 	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
@@ -29,11 +36,21 @@ var (
 		"bar",
 		"baz",
 	}
+
+	jsonValues = [listItemsCount]interface{}{
+		"foo",
+		42,
+		true,
+	}
+
+	timestampValue = time.Date(2021, time.September, 1, 12, 30, 0, 0, time.UTC)
 )
 
-func fillMessageFields(msg protoreflect.Message, level, iteration int) {
+// fillMessageFields fills msg with sample values. Only one member of every oneof is set,
+// the variant selects which one.
+func (g *Generator) fillMessageFields(msg protoreflect.Message, level, iteration, variant int) error {
 	if level > maxRecurseLevel {
-		return
+		return nil
 	}
 
 	fieldDescs := msg.Descriptor().Fields()
@@ -41,73 +58,183 @@ func fillMessageFields(msg protoreflect.Message, level, iteration int) {
 		fd := fieldDescs.Get(i)
 		fk := fd.Kind()
 
+		if od := fd.ContainingOneof(); od != nil && !od.IsSynthetic() {
+			if od.Fields().Get(variant%od.Fields().Len()) != fd {
+				continue
+			}
+		}
+
+		if example, ok := fieldExample(fd); ok {
+			if err := setFieldExample(msg, fd, example); err != nil {
+				return err
+			}
+			continue
+		}
+
 		switch {
 		case fd.IsList():
-			setList(msg.Mutable(fd).List(), fd, level)
+			if err := g.setList(msg.Mutable(fd).List(), fd, level); err != nil {
+				return err
+			}
 
 		case fd.IsMap():
-			setMap(msg.Mutable(fd).Map(), fd, level, iteration)
+			if err := g.setMap(msg.Mutable(fd).Map(), fd, level, iteration); err != nil {
+				return err
+			}
 
 		case fk == protoreflect.MessageKind || fk == protoreflect.GroupKind:
-			msg.Set(fd, messageValue(fd.Message(), level, iteration))
+			val, err := g.messageValue(fd.Message(), level, iteration)
+			if err != nil {
+				return err
+			}
+			msg.Set(fd, val)
 
 		default:
-			msg.Set(fd, scalarValue(fd.Kind(), iteration))
+			if val := g.scalarValue(fd, iteration); val.IsValid() {
+				msg.Set(fd, val)
+			}
+		}
+	}
+
+	return nil
+}
+
+// setFieldExample sets the field to the value given by the (twirp_doc.example) option.
+// The example is parsed as JSON value of the field and as a JSON string if it is not a valid JSON.
+func setFieldExample(msg protoreflect.Message, fd protoreflect.FieldDescriptor, example string) error {
+	quoted, err := json.Marshal(example)
+	if err != nil {
+		return err
+	}
+
+	tmp := msg.New()
+	for _, val := range []string{example, string(quoted)} {
+		if err = protojson.Unmarshal([]byte(fmt.Sprintf("{%q:%s}", fd.JSONName(), val)), tmp.Interface()); err == nil {
+			msg.Set(fd, tmp.Get(fd))
+			return nil
 		}
 	}
+
+	return fmt.Errorf("field %s: invalid example %q: %w", fd.FullName(), example, err)
 }
 
-func setList(list protoreflect.List, fd protoreflect.FieldDescriptor, level int) {
+func (g *Generator) setList(list protoreflect.List, fd protoreflect.FieldDescriptor, level int) error {
 	switch fd.Kind() { //nolint:exhaustive
 	case protoreflect.MessageKind, protoreflect.GroupKind:
 		for i := 0; i < listItemsCount; i++ {
-			val := list.NewElement()
-			fillMessageFields(val.Message(), level+1, i)
+			val, err := g.messageValue(fd.Message(), level, i)
+			if err != nil {
+				return err
+			}
 			list.Append(val)
 		}
 	default:
 		for i := 0; i < listItemsCount; i++ {
-			list.Append(scalarValue(fd.Kind(), i))
+			if val := g.scalarValue(fd, i); val.IsValid() {
+				list.Append(val)
+			}
 		}
 	}
+
+	return nil
 }
 
-func setMap(pmap protoreflect.Map, fd protoreflect.FieldDescriptor, level, iteration int) {
+func (g *Generator) setMap(pmap protoreflect.Map, fd protoreflect.FieldDescriptor, level, iteration int) error {
 	fields := fd.Message().Fields()
 	keyDesc := fields.ByNumber(1)
 	valDesc := fields.ByNumber(2)
 
-	pkey := scalarValue(keyDesc.Kind(), iteration)
+	pkey := g.scalarValue(keyDesc, iteration)
 
 	switch kind := valDesc.Kind(); kind { //nolint:exhaustive
 	case protoreflect.MessageKind, protoreflect.GroupKind:
-		pmap.Set(pkey.MapKey(), messageValue(valDesc.Message(), level, iteration))
+		val, err := g.messageValue(valDesc.Message(), level, iteration)
+		if err != nil {
+			return err
+		}
+		pmap.Set(pkey.MapKey(), val)
 	default:
-		pmap.Set(pkey.MapKey(), scalarValue(kind, iteration))
+		if val := g.scalarValue(valDesc, iteration); val.IsValid() {
+			pmap.Set(pkey.MapKey(), val)
+		}
 	}
+
+	return nil
 }
 
-func messageValue(md protoreflect.MessageDescriptor, level, iteration int) protoreflect.Value {
+func (g *Generator) messageValue(md protoreflect.MessageDescriptor, level, iteration int) (protoreflect.Value, error) {
 	switch md.FullName() {
 	case googleProtobufAny:
 		any, err := anypb.New(anyValues[iteration])
 		if err != nil {
-			panic(err)
+			return protoreflect.Value{}, err
 		}
-		return protoreflect.ValueOfMessage(any.ProtoReflect())
+		return protoreflect.ValueOfMessage(any.ProtoReflect()), nil
 
 	case googleProtobufDuration:
-		return protoreflect.ValueOfMessage(durationpb.New(13 * time.Second).ProtoReflect())
+		return protoreflect.ValueOfMessage(durationpb.New(13 * time.Second).ProtoReflect()), nil
 
-	default:
-		val := protoreflect.ValueOfMessage(dynamicpb.NewMessage(md))
-		fillMessageFields(val.Message(), level+1, 0)
-		return val
+	case googleProtobufTimestamp:
+		ts := timestamppb.New(timestampValue.Add(time.Duration(iteration) * time.Hour))
+		return protoreflect.ValueOfMessage(ts.ProtoReflect()), nil
+
+	case googleProtobufStruct:
+		s, err := structpb.NewStruct(map[string]interface{}{
+			"foo": stringValues[iteration],
+			"bar": map[string]interface{}{"baz": jsonValues[1]},
+		})
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfMessage(s.ProtoReflect()), nil
+
+	case googleProtobufValue:
+		v, err := structpb.NewValue(jsonValues[iteration])
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfMessage(v.ProtoReflect()), nil
+
+	case googleProtobufListValue:
+		l, err := structpb.NewList(jsonValues[:])
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfMessage(l.ProtoReflect()), nil
+
+	case googleProtobufFieldMask:
+		fm := &fieldmaskpb.FieldMask{Paths: []string{"foo", "bar.baz"}}
+		return protoreflect.ValueOfMessage(fm.ProtoReflect()), nil
+
+	case googleProtobufEmpty:
+		return protoreflect.ValueOfMessage(new(emptypb.Empty).ProtoReflect()), nil
 	}
+
+	msg := dynamicpb.NewMessage(md)
+	if examples := messageExamples(md); len(examples) > 0 {
+		if err := setMessageExample(msg, examples[iteration%len(examples)]); err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfMessage(msg), nil
+	}
+
+	if err := g.fillMessageFields(msg, level+1, iteration, iteration); err != nil {
+		return protoreflect.Value{}, err
+	}
+	return protoreflect.ValueOfMessage(msg), nil
 }
 
-func scalarValue(kind protoreflect.Kind, iteration int) protoreflect.Value {
-	switch kind { //nolint:exhaustive
+// setMessageExample fills msg with the JSON example given by the (twirp_doc.examples) option.
+func setMessageExample(msg protoreflect.Message, example string) error {
+	if err := protojson.Unmarshal([]byte(example), msg.Interface()); err != nil {
+		return fmt.Errorf("message %s: invalid example: %w", msg.Descriptor().FullName(), err)
+	}
+	return nil
+}
+
+// scalarValue returns the sample value of the scalar field or the invalid value if the enum field has no visible values.
+func (g *Generator) scalarValue(fd protoreflect.FieldDescriptor, iteration int) protoreflect.Value {
+	switch kind := fd.Kind(); kind { //nolint:exhaustive
 	case protoreflect.BoolKind:
 		return protoreflect.ValueOfBool(true)
 
@@ -136,8 +263,144 @@ func scalarValue(kind protoreflect.Kind, iteration int) protoreflect.Value {
 		return protoreflect.ValueOfString(stringValues[iteration])
 
 	case protoreflect.EnumKind:
-		return protoreflect.ValueOfEnum(1)
+		values := g.exampleEnumValues(fd.Enum())
+		if len(values) == 0 {
+			return protoreflect.Value{}
+		}
+		return protoreflect.ValueOfEnum(values[iteration%len(values)].Number())
 	}
 
-	panic(fmt.Errorf("FieldDescriptor.Kind %v is not valid", kind))
+	panic(fmt.Errorf("FieldDescriptor.Kind %v is not valid", fd.Kind()))
+}
+
+// exampleEnumValues returns the enum values to be used in examples. The values marked
+// by the (twirp_doc.example_value) option are preferred, otherwise all declared values are used
+// except the *_UNSPECIFIED zero value and the deprecated values. The values hidden from the enum table are never
+// used, if nothing else is left the example falls back to the first visible value. It returns nil if every value
+// is hidden.
+func (g *Generator) exampleEnumValues(ed protoreflect.EnumDescriptor) []protoreflect.EnumValueDescriptor {
+	var marked, declared, deprecated, visible []protoreflect.EnumValueDescriptor
+
+	seen := make(map[protoreflect.EnumNumber]bool)
+	values := ed.Values()
+	for i := 0; i < values.Len(); i++ {
+		ev := values.Get(i)
+		if seen[ev.Number()] || g.opts.Hidden(ev) {
+			continue
+		}
+		seen[ev.Number()] = true
+		if visible == nil {
+			visible = append(visible, ev)
+		}
+
+		if isExampleEnumValue(ev) {
+			marked = append(marked, ev)
+		}
+		if ev.Number() == 0 && strings.HasSuffix(string(ev.Name()), "_UNSPECIFIED") && values.Len() > 1 {
+			continue
+		}
+		if isDeprecated(ev) {
+			deprecated = append(deprecated, ev)
+			continue
+		}
+		declared = append(declared, ev)
+	}
+
+	switch {
+	case len(marked) > 0:
+		return marked
+	case len(declared) > 0:
+		return declared
+	case len(deprecated) > 0:
+		return deprecated
+	}
+	return visible
+}
+
+// oneofVariantsCount returns the number of examples required to show every member of the message oneofs.
+func oneofVariantsCount(md protoreflect.MessageDescriptor) int {
+	n := 1
+
+	oneofs := md.Oneofs()
+	for i := 0; i < oneofs.Len(); i++ {
+		od := oneofs.Get(i)
+		if od.IsSynthetic() {
+			continue
+		}
+		if l := od.Fields().Len(); l > n {
+			n = l
+		}
+	}
+
+	return n
+}
+
+// oneofVariantFields returns the oneof members set by the given variant.
+func oneofVariantFields(md protoreflect.MessageDescriptor, variant int) []protoreflect.FieldDescriptor {
+	oneofs := md.Oneofs()
+
+	fields := make([]protoreflect.FieldDescriptor, 0, oneofs.Len())
+	for i := 0; i < oneofs.Len(); i++ {
+		od := oneofs.Get(i)
+		if od.IsSynthetic() {
+			continue
+		}
+		fields = append(fields, od.Fields().Get(variant%od.Fields().Len()))
+	}
+
+	return fields
+}
+
+// clearFields recursively clears the fields of msg matching the filter.
+func clearFields(msg protoreflect.Message, filter func(protoreflect.FieldDescriptor) bool) {
+	msg.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if filter(fd) {
+			msg.Clear(fd)
+			return true
+		}
+
+		if fd.Message() == nil {
+			return true
+		}
+
+		switch {
+		case fd.IsList():
+			for i := 0; i < v.List().Len(); i++ {
+				clearFields(v.List().Get(i).Message(), filter)
+			}
+		case fd.IsMap():
+			if fd.MapValue().Message() != nil {
+				v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+					clearFields(mv.Message(), filter)
+					return true
+				})
+			}
+		default:
+			clearFields(v.Message(), filter)
+		}
+		return true
+	})
+}
+
+// invalidJSONField returns the innermost field which value can not be marshaled to JSON.
+func invalidJSONField(m protoreflect.Message) protoreflect.FieldDescriptor {
+	var invalid protoreflect.FieldDescriptor
+
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		tmp := m.New()
+		tmp.Set(fd, v)
+		if _, err := protojson.Marshal(tmp.Interface()); err == nil {
+			return true
+		}
+
+		invalid = fd
+		if fd.Message() != nil && !fd.IsList() && !fd.IsMap() {
+			if nested := invalidJSONField(v.Message()); nested != nil {
+				invalid = nested
+			}
+		}
+		return false
+	})
+
+	return invalid
 }
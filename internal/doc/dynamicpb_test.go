@@ -0,0 +1,21 @@
+package doc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/apipb"
+	"google.golang.org/protobuf/types/known/sourcecontextpb"
+)
+
+func TestMessageJSONFormat_InvalidField(t *testing.T) {
+	t.Parallel()
+
+	_, err := messageJSONFormat((&apipb.Api{
+		Name:          "api",
+		SourceContext: &sourcecontextpb.SourceContext{FileName: "invalid \xff"},
+	}).ProtoReflect())
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "message google.protobuf.Api: field google.protobuf.SourceContext.file_name: ")
+}
@@ -0,0 +1,72 @@
+package doc
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	contentTypeJSON     = "application/json"
+	contentTypeProtobuf = "application/protobuf"
+
+	hexDumpLineBytes    = 16
+	base64DumpLineChars = 76
+)
+
+var (
+	contentTypes = map[string]string{
+		ContentJSON:     contentTypeJSON,
+		ContentProtobuf: contentTypeProtobuf,
+	}
+
+	contentLabels = map[string]string{
+		ContentJSON:     "JSON",
+		ContentProtobuf: "Protobuf",
+	}
+)
+
+// messageBinaryFormat returns the dump of the message binary encoding.
+func messageBinaryFormat(m protoreflect.Message, dump string) (string, error) {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(m.Interface())
+	if err != nil {
+		return "", fmt.Errorf("message %s: %w", m.Descriptor().FullName(), err)
+	}
+
+	if dump == BinaryDumpBase64 {
+		return wrapLines(base64.StdEncoding.EncodeToString(b), base64DumpLineChars), nil
+	}
+	return hexDump(b), nil
+}
+
+// hexDump returns the space separated hex bytes, 16 bytes per line.
+func hexDump(b []byte) string {
+	lines := make([]string, 0, len(b)/hexDumpLineBytes+1)
+	for len(b) > 0 {
+		n := hexDumpLineBytes
+		if len(b) < n {
+			n = len(b)
+		}
+
+		line := make([]string, n)
+		for i := range line {
+			line[i] = hex.EncodeToString(b[i : i+1])
+		}
+		lines = append(lines, strings.Join(line, " "))
+		b = b[n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func wrapLines(s string, width int) string {
+	lines := make([]string, 0, len(s)/width+1)
+	for len(s) > width {
+		lines = append(lines, s[:width])
+		s = s[width:]
+	}
+	return strings.Join(append(lines, s), "\n")
+}
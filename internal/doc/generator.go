@@ -3,7 +3,7 @@ package doc
 import (
 	"fmt"
 	"io"
-	"sort"
+	"strconv"
 	"strings"
 
 	"google.golang.org/protobuf/compiler/protogen"
@@ -15,281 +15,689 @@ import (
 )
 
 type Generator struct {
-	baseURL  string
-	writer   io.Writer
-	doc      *md.Document
-	messages map[string]*protogen.Message
-	enums    map[string]*protogen.Enum
+	opts   *Options
+	writer io.Writer
+	doc    *md.Document
+	// document is the model rendered into doc, the model references of the comments are resolved against it.
+	document *Document
 }
 
-func NewGenerator(w io.Writer, baseURL string) *Generator {
+func NewGenerator(w io.Writer, opts *Options) *Generator {
 	return &Generator{
-		baseURL: baseURL,
-		writer:  w,
+		opts:   opts,
+		writer: w,
 	}
 }
 
-func (g *Generator) GenerateServiceDocument(service *protogen.Service) error { //nolint:funlen
-	g.messages = make(map[string]*protogen.Message)
-	g.enums = make(map[string]*protogen.Enum)
+// GenerateServiceDocument writes the document of the single service.
+func (g *Generator) GenerateServiceDocument(service *protogen.Service) error {
+	d, err := g.BuildDocument(string(service.Desc.Name()), []*protogen.Service{service}, false)
+	if err != nil {
+		return err
+	}
+	if len(d.Services) == 0 {
+		return fmt.Errorf("service %s is hidden", service.Desc.FullName())
+	}
+	g.begin(d)
+	if g.opts.Template != "" {
+		return g.generateTemplate(d)
+	}
 
-	g.doc = new(md.Document)
-	g.doc.Append(md.TH1(string(service.Desc.Name())))
-	g.doc.Append(md.P(md.Code(string(service.Desc.FullName()))))
+	s := d.Services[0]
+	g.doc.Append(md.TH1(s.Name))
+	g.doc.Append(md.P(md.Code(s.FullName)))
+	g.printDeprecated(s.Deprecated, "service")
 
-	if desc := descriptionBlock(service.Comments.Leading); desc != nil {
+	if desc := g.commentsBlock(s.Comments); desc != nil {
 		g.doc.Append(desc)
 	}
 
-	methodListIems := make([]md.Block, 0, len(service.Methods))
-	for _, method := range service.Methods {
-		g.collectModels(method.Input)
-		g.collectModels(method.Output)
+	g.doc.Append(md.TH3("Methods"))
+	g.doc.Append(methodsList(s))
+	g.printModelsIndex(d)
 
-		mname := string(method.Desc.Name())
-		methodListIems = append(methodListIems, md.LinkToHeader(fmt.Sprintf("POST /%s", mname), mname))
+	g.doc.Append(md.Line())
+
+	g.doc.Append(md.TH2("Methods"))
+	g.doc.Append(baseURLBlock(s))
+
+	if err := g.printMethods(s); err != nil {
+		return err
 	}
 
-	g.doc.Append(md.TH3("Methods"))
-	g.doc.Append(md.UL(methodListIems...))
+	g.printModels(d)
+	g.printErrors()
+
+	return g.doc.Generate(g.writer)
+}
+
+// GenerateDocument writes the combined document of several services with the shared models and errors sections.
+func (g *Generator) GenerateDocument(title string, services []*protogen.Service) error {
+	d, err := g.BuildDocument(title, services, true)
+	if err != nil {
+		return err
+	}
+	g.begin(d)
+	if g.opts.Template != "" {
+		return g.generateTemplate(d)
+	}
+
+	g.doc.Append(md.TH1(d.Title))
+
+	serviceListItems := make([]md.Block, 0, len(d.Services))
+	for _, s := range d.Services {
+		serviceListItems = append(serviceListItems, md.G(
+			md.Link(s.Anchor, s.Name),
+			deprecatedBadge(s.Deprecated),
+			md.T(": "),
+			methodsInline(s),
+		))
+	}
+	g.doc.Append(md.TH3("Services"))
+	g.doc.Append(md.UL(serviceListItems...))
+	g.printModelsIndex(d)
 
-	modelsCount := len(g.messages) + len(g.enums)
-	modelKeys := make([]string, 0, modelsCount)
-	if modelsCount > 0 {
-		for k := range g.enums {
-			modelKeys = append(modelKeys, k)
+	g.doc.Append(md.Line())
+
+	for _, s := range d.Services {
+		g.doc.Append(md.TH2(s.FullName))
+		g.printDeprecated(s.Deprecated, "service")
+
+		if desc := g.commentsBlock(s.Comments); desc != nil {
+			g.doc.Append(desc)
 		}
-		for k := range g.messages {
-			modelKeys = append(modelKeys, k)
+		g.doc.Append(baseURLBlock(s))
+
+		if err := g.printMethods(s); err != nil {
+			return fmt.Errorf("service %s: %w", s.FullName, err)
 		}
-		sort.Strings(modelKeys)
-		modelListItems := make([]md.Block, 0, len(modelKeys))
-		for _, k := range modelKeys {
-			var name string
-			if m, ok := g.messages[k]; ok {
-				name = string(m.Desc.FullName())
-			} else {
-				name = string(g.enums[k].Desc.FullName())
-			}
-			modelListItems = append(modelListItems, md.LinkToHeader(name, name))
+	}
+
+	g.printModels(d)
+	g.printErrors()
+
+	return g.doc.Generate(g.writer)
+}
+
+// begin starts rendering the new Markdown document of the model, nil for the document without models.
+func (g *Generator) begin(d *Document) {
+	g.doc = new(md.Document)
+	g.document = d
+}
+
+// visibleServices returns the services which are not hidden by the options.
+func (g *Generator) visibleServices(services []*protogen.Service) []*protogen.Service {
+	visible := make([]*protogen.Service, 0, len(services))
+	for _, service := range services {
+		if !g.opts.Hidden(service.Desc) {
+			visible = append(visible, service)
 		}
-		g.doc.Append(md.TH3("Models"))
-		g.doc.Append(md.UL(modelListItems...))
 	}
+	return visible
+}
 
-	g.doc.Append(md.Line())
+// methodHeaderPath returns the path of the method section header. The combined document uses the full method path
+// to keep the anchors unique.
+func methodHeaderPath(service *protogen.Service, method *protogen.Method, combined bool) string {
+	if combined {
+		return fmt.Sprintf("/%s/%s", service.Desc.FullName(), method.Desc.Name())
+	}
+	return fmt.Sprintf("/%s", method.Desc.Name())
+}
 
-	g.doc.Append(md.TH2("Methods"))
-	g.doc.Append(md.P(
-		md.T("Base URL: "),
-		md.Code(g.baseURL+"/"+string(service.Desc.FullName())),
-	))
+func methodsList(s *Service) md.Block {
+	items := make([]md.Block, 0, len(s.Methods))
+	for _, m := range s.Methods {
+		items = append(items, md.G(md.Link(m.Anchor, m.Name), deprecatedBadge(m.Deprecated)))
+	}
+	return md.UL(items...)
+}
 
-	for _, method := range service.Methods {
-		g.doc.Append(md.H3(md.G(
-			md.T("POST "),
-			md.Code(fmt.Sprintf("/%s", method.Desc.Name())),
-		)))
-		if desc := descriptionBlock(method.Comments.Leading); desc != nil {
+func methodsInline(s *Service) md.Block {
+	items := make([]md.Block, 0, len(s.Methods)*2) //nolint:gomnd
+	for i, m := range s.Methods {
+		if i > 0 {
+			items = append(items, md.T(", "))
+		}
+		items = append(items, md.Link(m.Anchor, m.Name), deprecatedBadge(m.Deprecated))
+	}
+	return md.G(items...)
+}
+
+func (g *Generator) printModelsIndex(d *Document) {
+	models := d.models()
+	if len(models) == 0 {
+		return
+	}
+
+	items := make([]md.Block, 0, len(models))
+	for _, model := range models {
+		switch m := model.(type) {
+		case *Message:
+			items = append(items, md.G(md.Link(m.Anchor, m.FullName), deprecatedBadge(m.Deprecated)))
+		case *Enum:
+			items = append(items, md.G(md.Link(m.Anchor, m.FullName), deprecatedBadge(m.Deprecated)))
+		}
+	}
+
+	g.doc.Append(md.TH3("Models"))
+	g.doc.Append(md.UL(items...))
+}
+
+func (g *Generator) printMethods(s *Service) error {
+	for _, m := range s.Methods {
+		g.doc.Append(md.H3(md.T("POST "), md.Code(m.HeadingPath)))
+		g.printDeprecated(m.Deprecated, "method")
+
+		if m.Streaming != "" {
+			g.doc.Append(md.Quote(
+				md.TB("Warning:"),
+				md.T(fmt.Sprintf(" the method is declared as %s RPC. Twirp does not support streaming, "+
+					"the method can not be called with a Twirp client.", m.Streaming)),
+			))
+		}
+		if desc := g.commentsBlock(m.Comments); desc != nil {
 			g.doc.Append(desc)
 		}
 
 		g.doc.Append(md.TH4("Request"))
-		g.doc.Append(md.P(md.Code(string(method.Input.Desc.FullName()))))
-		g.doc.Append(md.P(md.Code(fmt.Sprintf("POST /%s/%s", service.Desc.FullName(), method.Desc.Name()))))
-		g.doc.Append(md.CodeBlock(messageJSONString(method.Input.Desc), "json"))
-		g.printMessageFields(method.Input)
+		g.doc.Append(md.P(md.Code(m.Request.FullName)))
+		g.doc.Append(md.P(md.Code("POST " + m.Path)))
+		if err := g.printMessageExamples(m.Request); err != nil {
+			return fmt.Errorf("method %s: %w", m.Name, err)
+		}
+		g.printMessageFields(m.Request)
 
 		g.doc.Append(md.TH4("Response"))
-		g.doc.Append(md.P(md.Code(string(method.Output.Desc.FullName()))))
+		g.doc.Append(md.P(md.Code(m.Response.FullName)))
 		g.doc.Append(md.P(md.Code("HTTP 200 OK")))
-		g.doc.Append(md.CodeBlock(messageJSONString(method.Output.Desc), "json"))
-		g.printMessageFields(method.Output)
+		if err := g.printMessageExamples(m.Response); err != nil {
+			return fmt.Errorf("method %s: %w", m.Name, err)
+		}
+		g.printMessageFields(m.Response)
+
+		if err := g.printMethodErrors(m.Errors); err != nil {
+			return err
+		}
+		g.printSamples(m.Samples)
 	}
 
-	if modelsCount > 0 {
-		g.doc.Append(md.TH2("Models"))
+	return nil
+}
 
-		for _, k := range modelKeys {
-			if m, ok := g.messages[k]; ok {
-				g.doc.Append(md.TH3(string(m.Desc.FullName())))
-				g.printMessageFields(m)
-				continue
-			}
+func (g *Generator) printModels(d *Document) {
+	models := d.models()
+	if len(models) == 0 {
+		return
+	}
 
-			e := g.enums[k]
-			g.doc.Append(md.TH3(string(e.Desc.FullName())))
-			g.printEnumItems(e)
+	g.doc.Append(md.TH2("Models"))
+
+	for _, model := range models {
+		switch m := model.(type) {
+		case *Message:
+			g.doc.Append(md.TH3(m.FullName))
+			g.printDeprecated(m.Deprecated, "message")
+			g.printMessageFields(m)
+		case *Enum:
+			g.doc.Append(md.TH3(m.FullName))
+			g.printDeprecated(m.Deprecated, "enum")
+			g.printEnumItems(m)
 		}
 	}
+}
+
+// printErrors prints the global Twirp errors section according to the errors table mode.
+func (g *Generator) printErrors() {
+	switch g.opts.Errors {
+	case ErrorsOmit:
+		return
+	case ErrorsLink:
+		g.doc.Append(md.TH2("Twirp Errors"))
+		g.doc.Append(md.P(md.T("See "), md.Link(g.opts.ErrorsURL, "Twirp error codes"), md.T(".")))
+	case ErrorsCollapse:
+		g.doc.Append(md.TH2("Twirp Errors"))
+		g.doc.Append(md.Details("Twirp error codes", twirpErrorCodesTable()))
+	default:
+		g.doc.Append(md.TH2("Twirp Errors"))
+		g.doc.Append(twirpErrorCodesTable())
+	}
+}
 
-	g.doc.Append(md.TH2("Twirp Errors"))
+// GenerateErrors writes the standalone document of the Twirp error codes linked in the ErrorsLink mode.
+func (g *Generator) GenerateErrors() error {
+	g.begin(nil)
+
+	g.doc.Append(md.TH1("Twirp Errors"))
 	g.doc.Append(twirpErrorCodesTable())
 
 	return g.doc.Generate(g.writer)
 }
 
-func (g *Generator) collectModels(message *protogen.Message) {
-	for _, field := range message.Fields {
-		switch field.Desc.Kind() { //nolint:exhaustive
-		case protoreflect.MessageKind:
-			if !field.Desc.IsMap() {
-				if _, ok := protoKnownTypeLabels[field.Message.Desc.FullName()]; ok {
-					break
-				}
-				g.messages[string(field.Message.Desc.FullName())] = field.Message
+// printMethodErrors prints the table of the Twirp errors declared for the method.
+func (g *Generator) printMethodErrors(errs []*ErrorSpec) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	t := new(md.Table)
+	t.AddColumn("Twirp Error Code", md.AlignLeft)
+	t.AddColumn("HTTP Status", md.AlignCenter)
+	t.AddColumn("Description", md.AlignLeft)
+
+	for _, e := range errs {
+		t.AppendRow(md.Code(e.Code), md.Code(strconv.Itoa(e.HTTPStatus)), g.text(e.Description))
+	}
+
+	g.doc.Append(md.TH4("Errors"))
+	g.doc.Append(t)
+
+	if meta := g.errorMetaTable(errs); meta != nil {
+		g.doc.Append(md.P(md.TB("Error meta")))
+		g.doc.Append(meta)
+	}
+
+	e := exampleError(errs)
+	example, err := errorJSONFormat(e)
+	return g.printExample(md.G(md.T("Example of the "), md.Code(e.Code), md.T(" error response body:")),
+		example, "json", err)
+}
+
+// printSamples prints the method call samples.
+func (g *Generator) printSamples(samples []*Sample) {
+	if len(samples) == 0 {
+		return
+	}
+
+	g.doc.Append(md.TH4("Samples"))
+	for _, s := range samples {
+		if len(samples) > 1 {
+			g.doc.Append(md.P(md.TB(s.Label)))
+		}
+		g.doc.Append(md.CodeBlock(s.Code, s.Syntax))
+	}
+}
+
+// errorMetaTable returns the table of the meta keys declared for the method errors or nil if there are none.
+func (g *Generator) errorMetaTable(errs []*ErrorSpec) *md.Table {
+	t := new(md.Table)
+	t.AddColumn("Twirp Error Code", md.AlignLeft)
+	t.AddColumn("Meta Key", md.AlignLeft)
+	t.AddColumn("Description", md.AlignLeft)
+
+	empty := true
+	for _, e := range errs {
+		for _, m := range e.Meta {
+			t.AppendRow(md.Code(e.Code), md.Code(m.Key), g.text(m.Description))
+			empty = false
+		}
+	}
+
+	if empty {
+		return nil
+	}
+	return t
+}
+
+// serviceMethods returns the methods to document according to the streaming methods handling mode.
+func (g *Generator) serviceMethods(service *protogen.Service) ([]*protogen.Method, error) {
+	methods := make([]*protogen.Method, 0, len(service.Methods))
+
+	for _, method := range service.Methods {
+		if g.opts.Hidden(method.Desc) {
+			continue
+		}
+		if kind := streamingKind(method); kind != "" {
+			switch g.opts.Streaming {
+			case StreamingSkip:
+				continue
+			case StreamingError:
+				return nil, fmt.Errorf("method %s: %s RPC is not supported by Twirp", method.Desc.FullName(), kind)
 			}
-			g.collectModels(field.Message)
-		case protoreflect.EnumKind:
-			g.enums[string(field.Enum.Desc.FullName())] = field.Enum
 		}
+		methods = append(methods, method)
 	}
+
+	return methods, nil
 }
 
-func (g *Generator) printMessageFields(message *protogen.Message) {
-	if desc := descriptionBlock(message.Comments.Leading); desc != nil {
+func (g *Generator) printMessageFields(m *Message) {
+	if desc := g.commentsBlock(m.Comments); desc != nil {
 		g.doc.Append(desc)
 	}
+	g.doc.Append(g.fieldsTable(m))
+}
 
+// fieldsTable returns the table of the message fields, the oneof members follow the oneof row.
+func (g *Generator) fieldsTable(m *Message) *md.Table {
 	t := new(md.Table)
 	t.AddColumn("Field", md.AlignLeft)
 	t.AddColumn("Type", md.AlignCenter)
 	t.AddColumn("Description", md.AlignLeft)
 
-	for _, field := range message.Fields {
-		fieldComment := descriptionCellText(field.Comments.Leading)
-		if fieldComment == nil {
-			fieldComment = md.T("")
+	printed := make(map[string]bool)
+	for _, f := range m.Fields {
+		if f.Oneof == "" {
+			t.AppendRow(deprecatedName(f.Deprecated, md.Code(f.JSONName)), typeBlock(f.Type),
+				g.descriptionCell(f.Deprecated, f.Comments))
+			continue
+		}
+
+		if printed[f.Oneof] {
+			continue
+		}
+		printed[f.Oneof] = true
+
+		for _, oneof := range m.Oneofs {
+			if oneof.Name == f.Oneof {
+				t.AppendRow(md.EI(oneof.Name), md.T("exactly one of"), g.descriptionCell(false, oneof.Comments))
+			}
+		}
+
+		for _, of := range m.Fields {
+			if of.Oneof != f.Oneof {
+				continue
+			}
+			t.AppendRow(md.G(md.T("↳ "), deprecatedName(of.Deprecated, md.Code(of.JSONName))), typeBlock(of.Type),
+				g.descriptionCell(of.Deprecated, of.Comments))
 		}
-		t.AppendRow(
-			md.Code(field.Desc.JSONName()),
-			fieldTypeBlock(field),
-			fieldComment,
-		)
 	}
 
-	g.doc.Append(t)
+	return t
 }
 
-func (g *Generator) printEnumItems(enum *protogen.Enum) {
-	if desc := descriptionBlock(enum.Comments.Leading); desc != nil {
+// printMessageExamples prints the examples of the message in every documented encoding.
+func (g *Generator) printMessageExamples(m *Message) error {
+	for _, encoding := range g.opts.Encodings {
+		if len(g.opts.Encodings) > 1 || encoding != ContentJSON {
+			g.doc.Append(md.P(md.TB(contentLabels[encoding]), md.T(" "), md.Code("Content-Type: "+contentTypes[encoding])))
+		}
+
+		for _, e := range m.Examples {
+			var err error
+			switch encoding {
+			case ContentJSON:
+				err = g.printExample(exampleLabel(e), e.JSON, "json", e.Err)
+			case ContentProtobuf:
+				err = g.printExample(exampleLabel(e), e.Binary, "", e.Err)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// exampleLabel returns the label of the oneof variant example or nil for the single example.
+func exampleLabel(e *Example) md.Block {
+	if len(e.Variant) == 0 {
+		return nil
+	}
+
+	label := make([]md.Block, 0, len(e.Variant)*2+1)
+	label = append(label, md.T("Example with "))
+	for i, name := range e.Variant {
+		if i > 0 {
+			label = append(label, md.T(", "))
+		}
+		label = append(label, md.Code(name))
+	}
+	return md.I(label...)
+}
+
+// printExample prints the labeled example or the warning instead of it if the example generation has failed
+// and invalid examples are allowed.
+func (g *Generator) printExample(label md.Block, example, syntax string, err error) error {
+	if label != nil {
+		g.doc.Append(md.P(label))
+	}
+
+	if err != nil {
+		if g.opts.InvalidExamples != InvalidExamplesWarn {
+			return err
+		}
+		g.doc.Append(md.Quote(
+			md.TB("Warning:"),
+			md.T(" the example can not be generated: "),
+			md.E(strings.Join(strings.Fields(err.Error()), " ")),
+		))
+		return nil
+	}
+
+	if syntax == "" {
+		g.doc.Append(md.CodeBlock(example))
+	} else {
+		g.doc.Append(md.CodeBlock(example, syntax))
+	}
+	return nil
+}
+
+func (g *Generator) printEnumItems(e *Enum) {
+	if desc := g.commentsBlock(e.Comments); desc != nil {
 		g.doc.Append(desc)
 	}
+	g.doc.Append(g.enumTable(e))
+}
 
+// enumTable returns the table of the enum values.
+func (g *Generator) enumTable(e *Enum) *md.Table {
 	table := new(md.Table)
 	table.AddColumn("Value", md.AlignLeft)
 	table.AddColumn("Description", md.AlignLeft)
 
-	for _, ev := range enum.Values {
-		desc1 := descriptionCellText(ev.Comments.Leading)
-		desc2 := descriptionCellText(ev.Comments.Trailing)
+	for _, v := range e.Values {
+		table.AppendRow(deprecatedName(v.Deprecated, md.Code(v.Name)), g.descriptionCell(v.Deprecated, v.Comments))
+	}
+
+	return table
+}
 
-		desc := md.T("")
-		if desc1 != nil {
-			if desc2 != nil {
-				desc = md.G(md.CellP(desc1), md.CellP(desc2))
-			} else {
-				desc = desc1
+// oneofVariants returns the oneof variants of the message examples, the variants setting the hidden fields
+// are skipped.
+func (g *Generator) oneofVariants(mdesc protoreflect.MessageDescriptor) []int {
+	n := oneofVariantsCount(mdesc)
+
+	variants := make([]int, 0, n)
+	for i := 0; i < n; i++ {
+		visible := true
+		for _, fd := range oneofVariantFields(mdesc, i) {
+			if g.opts.Hidden(fd) {
+				visible = false
 			}
-		} else if desc2 != nil {
-			desc = desc2
 		}
+		if visible {
+			variants = append(variants, i)
+		}
+	}
 
-		table.AppendRow(md.Code(string(ev.Desc.Name())), desc)
+	if len(variants) == 0 {
+		return []int{0}
 	}
+	return variants
+}
 
-	g.doc.Append(table)
+// sampleMessage returns the message filled with the sample values.
+func (g *Generator) sampleMessage(mdesc protoreflect.MessageDescriptor, variant int) (protoreflect.Message, error) {
+	m := dynamicpb.NewMessage(mdesc)
+	if err := g.fillMessageFields(m, 0, 0, variant); err != nil {
+		return nil, err
+	}
+	clearFields(m, g.hiddenField)
+	return m, nil
 }
 
-func messageJSONString(mdesc protoreflect.MessageDescriptor) string {
+// declaredExampleMessage returns the message filled with the example declared by the (twirp_doc.examples) option.
+func (g *Generator) declaredExampleMessage(mdesc protoreflect.MessageDescriptor, example string) (protoreflect.Message, error) {
 	m := dynamicpb.NewMessage(mdesc)
-	fillMessageFields(m, 0, 0)
+	if err := setMessageExample(m, example); err != nil {
+		return nil, err
+	}
+	clearFields(m, g.hiddenField)
+	return m, nil
+}
+
+func (g *Generator) hiddenField(fd protoreflect.FieldDescriptor) bool {
+	return g.opts.Hidden(fd)
+}
 
+func messageJSONFormat(m protoreflect.Message) (string, error) {
 	j := protojson.MarshalOptions{
 		Multiline: true,
 		Indent:    "  ",
 	}
-	return j.Format(m)
-}
-
-func fieldTypeBlock(field *protogen.Field) md.Block {
-	var block md.Block
-
-	//nolint:exhaustive
-	switch field.Desc.Kind() {
-	case protoreflect.MessageKind:
-		msg := field.Message
-		name := msg.Desc.FullName()
 
-		if s, ok := protoKnownTypeLabels[name]; ok {
-			block = md.T(s)
-			break
+	b, err := j.Marshal(m.Interface())
+	if err != nil {
+		if fd := invalidJSONField(m); fd != nil {
+			return "", fmt.Errorf("message %s: field %s: %w", m.Descriptor().FullName(), fd.FullName(), err)
 		}
+		return "", fmt.Errorf("message %s: %w", m.Descriptor().FullName(), err)
+	}
 
-		if field.Desc.IsMap() {
-			var key, val *protogen.Field
-			for _, f := range field.Message.Fields {
-				if f.Desc == field.Desc.MapKey() {
-					key = f
-				} else {
-					val = f
-				}
-			}
-			block = md.G(
-				md.T("map "),
-				fieldTypeBlock(key),
-				md.T(" to "),
-				fieldTypeBlock(val),
-			)
-			break
-		}
-
-		block = md.LinkToHeader(string(name), string(name))
-
-	case protoreflect.EnumKind:
-		enum := field.Desc.Enum()
-		block = md.Link(string(enum.Name()), string(enum.FullName()))
+	return string(b), nil
+}
 
+// typeBlock renders the field type, the documented models are linked.
+func typeBlock(t *Type) md.Block {
+	var block md.Block
+	switch {
+	case t.MapKey != nil:
+		block = md.G(md.T("map "), typeBlock(t.MapKey), md.T(" to "), typeBlock(t.MapValue))
+	case t.Model != "":
+		block = md.Link(t.Anchor, t.Label)
 	default:
-		if s, ok := protoKindTypes[field.Desc.Kind()]; ok {
-			block = md.T(s)
-		}
+		block = md.T(t.Label)
 	}
 
-	if block == nil {
-		panic(fmt.Errorf("field %s: unknown kind %s", field.Desc.FullName(), field.Desc.Kind()))
+	if t.Repeated {
+		return md.G(md.T("array of "), block)
 	}
+	return block
+}
 
-	if field.Desc.IsList() {
-		return md.G(
-			md.T("array of "),
-			block,
-		)
+// streamingKind returns the kind of the streaming method or an empty string for the unary one.
+func streamingKind(method *protogen.Method) string {
+	switch client, server := method.Desc.IsStreamingClient(), method.Desc.IsStreamingServer(); {
+	case client && server:
+		return "bidirectional streaming"
+	case client:
+		return "client streaming"
+	case server:
+		return "server streaming"
 	}
-	return block
+	return ""
 }
 
-func descriptionBlock(c protogen.Comments) md.Block {
+func baseURLBlock(s *Service) md.Block {
+	return md.P(
+		md.T("Base URL: "),
+		md.Code(s.BaseURL),
+	)
+}
+
+func (g *Generator) descriptionBlock(c protogen.Comments) md.Block {
+	c = withoutDirectives(c)
 	if c == "" {
 		return nil
 	}
 
+	if g.opts.Comments == CommentsMarkdown {
+		return md.P(md.T(g.markdownComment(c)))
+	}
+
 	lines := strings.Split(strings.TrimSpace(string(c)), "\n\n")
 
 	blocks := make([]md.Block, 0, len(lines))
 	for _, l := range lines {
-		blocks = append(blocks, md.P(md.TI(strings.ReplaceAll(strings.TrimSpace(l), "\n ", "\n"))))
+		text := g.text(strings.ReplaceAll(strings.TrimSpace(l), "\n ", "\n"))
+		if g.opts.Comments == CommentsPlain {
+			blocks = append(blocks, md.P(text))
+		} else {
+			blocks = append(blocks, md.P(md.I(text)))
+		}
 	}
 
 	return md.G(blocks...)
 }
 
-func descriptionCellText(c protogen.Comments) md.Block {
+func (g *Generator) descriptionCellText(c protogen.Comments) md.Block {
+	c = withoutDirectives(c)
 	s := string(c)
 	if s == "" {
 		return nil
 	}
-	return md.T(strings.TrimSpace(strings.ReplaceAll(strings.ReplaceAll(s, "\n\n", "<br/>"), "\n", " ")))
+
+	if g.opts.Comments == CommentsMarkdown {
+		return md.T(g.markdownCommentCell(c))
+	}
+
+	if g.opts.RawComments {
+		return md.T(strings.TrimSpace(strings.ReplaceAll(strings.ReplaceAll(s, "\n\n", "<br/>"), "\n", " ")))
+	}
+
+	paragraphs := strings.Split(strings.TrimSpace(s), "\n\n")
+
+	blocks := make([]md.Block, 0, len(paragraphs)*2) //nolint:gomnd
+	for i, p := range paragraphs {
+		if i > 0 {
+			blocks = append(blocks, md.T("<br/>"))
+		}
+		blocks = append(blocks, md.E(strings.Join(strings.Fields(p), " ")))
+	}
+
+	return md.G(blocks...)
+}
+
+// text renders the text taken from the proto comments, the text is escaped unless the comments are trusted.
+func (g *Generator) text(s string) md.Block {
+	if g.opts.RawComments || g.opts.Comments == CommentsMarkdown {
+		return md.T(s)
+	}
+	return md.E(s)
+}
+
+// printDeprecated prints the deprecation callout of the deprecated element.
+func (g *Generator) printDeprecated(deprecated bool, kind string) {
+	if deprecated {
+		g.doc.Append(md.Quote(
+			md.TB("Deprecated:"),
+			md.T(fmt.Sprintf(" the %s is deprecated and may be removed in the future.", kind)),
+		))
+	}
+}
+
+// descriptionCell returns the description cell of the field, oneof or enum value. The description of the deprecated
+// element is prefixed with the deprecation notice.
+func (g *Generator) descriptionCell(deprecated bool, c *Comments) md.Block {
+	text := g.commentsCell(c)
+
+	switch {
+	case deprecated && text != nil:
+		return md.G(md.TB("Deprecated."), md.T(" "), text)
+	case deprecated:
+		return md.TB("Deprecated.")
+	case text != nil:
+		return text
+	}
+	return md.T("")
+}
+
+// deprecatedName strikes through the name of the deprecated field or enum value.
+func deprecatedName(deprecated bool, name md.Block) md.Block {
+	if !deprecated {
+		return name
+	}
+	return md.S(name)
+}
+
+// deprecatedBadge returns the deprecation marker of the index item.
+func deprecatedBadge(deprecated bool) md.Block {
+	if !deprecated {
+		return md.T("")
+	}
+	return md.G(md.T(" "), md.Code("deprecated"))
 }
 
 func twirpErrorCodesTable() md.Block {
@@ -299,56 +707,12 @@ func twirpErrorCodesTable() md.Block {
 	t.AddColumn("HTTP Status", md.AlignCenter)
 	t.AddColumn("Description", md.AlignLeft)
 
-	t.AppendRow(md.Code("invalid_argument"), md.Code("400"), md.T("The client specified an invalid argument. "+
-		"This indicates arguments that are invalid regardless of the state of the system "+
-		"(i.e. a malformed file name, required argument, number out of range, etc.)."))
-	t.AppendRow(md.Code("malformed"), md.Code("400"), md.T("The client sent a message which could not be decoded. "+
-		"This may mean that the message was encoded improperly or that the client and server have incompatible "+
-		"message definitions."))
-	t.AppendRow(md.Code("out_of_range"), md.Code("400"), md.T("The operation was attempted past the valid range. "+
-		"For example, seeking or reading past end of a paginated collection. Unlike \"invalid_argument\", "+
-		"this error indicates a problem that may be fixed if the system state changes "+
-		"(i.e. adding more items to the collection). There is a fair bit of overlap between "+
-		"\"failed_precondition\" and \"out_of_range\". We recommend using \"out_of_range\" "+
-		"(the more specific error) when it applies so that callers who are iterating through a space"+
-		" can easily look for an \"out_of_range\" error to detect when they are done."))
-	t.AppendRow(md.Code("unauthenticated"), md.Code("401"), md.T("The request does not have valid authentication "+
-		"credentials for the operation."))
-	t.AppendRow(md.Code("permission_denied"), md.Code("403"), md.T("The caller does not have permission to execute "+
-		"the specified operation. It must not be used if the caller cannot be identified "+
-		"(use \"unauthenticated\" instead)."))
-	t.AppendRow(md.Code("bad_route"), md.Code("404"), md.T("The requested URL path wasn't routable to a Twirp "+
-		"service and method. This is returned by generated server code and should not be returned by "+
-		"application code (use \"not_found\" or \"unimplemented\" instead)."))
-	t.AppendRow(md.Code("not_found"), md.Code("404"), md.T("Some requested entity was not found."))
-	t.AppendRow(md.Code("canceled"), md.Code("408"), md.T("The operation was cancelled."))
-	t.AppendRow(md.Code("deadline_exceeded"), md.Code("408"), md.T("Operation expired before completion. "+
-		"For operations that change the state of the system, this error may be returned even if the operation "+
-		"has completed successfully (timeout)."))
-	t.AppendRow(md.Code("already_exists"), md.Code("409"), md.T("An attempt to create an entity failed because one "+
-		"already exists."))
-	t.AppendRow(md.Code("aborted"), md.Code("409"), md.T("The operation was aborted, typically due to "+
-		"a concurrency issue like sequencer check failures, transaction aborts, etc."))
-	t.AppendRow(md.Code("failed_precondition"), md.Code("412"), md.T("The operation was rejected because the "+
-		"system is not in a state required for the operation's execution. For example, doing an rmdir "+
-		"operation on a directory that is non-empty, or on a non-directory object, or when having conflicting "+
-		"read-modify-write on the same resource."))
-	t.AppendRow(md.Code("resource_exhausted"), md.Code("429"), md.T("Some resource has been exhausted or "+
-		"rate-limited, perhaps a per-user quota, or perhaps the entire file system is out of space."))
-	t.AppendRow(md.Code("unknown"), md.Code("500"), md.T("An unknown error occurred. For example, "+
-		"this can be used when handling errors raised by APIs that do not return any error information."))
-	t.AppendRow(md.Code("internal"), md.Code("500"), md.T("When some invariants expected by the underlying system "+
-		"have been broken. In other words, something bad happened in the library or backend service. "+
-		"Twirp specific issues like wire and serialization problems are also reported as \"internal\" errors."))
-	t.AppendRow(md.Code("unavailable"), md.Code("500"), md.T("The service is currently unavailable. This is most "+
-		"likely a transient condition and may be corrected by retrying with a backoff."))
-	t.AppendRow(md.Code("unimplemented"), md.Code("501"), md.T("The operation is not implemented or not "+
-		"supported/enabled in this service."))
-	t.AppendRow(md.Code("dataloss"), md.Code("503"), md.T("The operation resulted in unrecoverable data loss or "+
-		"corruption."))
+	for _, c := range twirpErrorCodes {
+		t.AppendRow(md.Code(c.Code), md.Code(strconv.Itoa(c.HTTPStatus)), md.T(c.Description))
+	}
 
 	return md.G(
-		md.P(md.Link("https://twitchtv.github.io/twirp/docs/spec_v7.html#error-codes", "Official documentation")),
+		md.P(md.Link(twirpErrorCodesURL, "Official documentation")),
 		t,
 	)
 }
@@ -0,0 +1,641 @@
+package doc_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/albenik/twirp-doc-gen/internal/doc"
+	"github.com/albenik/twirp-doc-gen/internal/plugin"
+)
+
+const testBaseURL = "https://api.example.com/twirp"
+
+var (
+	jsonBlockRx   = regexp.MustCompile("(?s)```json\n(.*?)\n```\n")
+	binaryBlockRx = regexp.MustCompile("(?s)\n\n```\n(.*?)\n```\n")
+)
+
+func testOptions() *doc.Options {
+	return &doc.Options{
+		BaseURL:         testBaseURL,
+		Streaming:       doc.StreamingWarn,
+		InvalidExamples: doc.InvalidExamplesError,
+		Comments:        doc.CommentsItalic,
+		Deprecated:      doc.DeprecatedMark,
+		Encodings:       []string{doc.ContentJSON},
+		BinaryDump:      doc.BinaryDumpHex,
+		Errors:          doc.ErrorsFull,
+	}
+}
+
+// loadPlugin builds the protoc plugin from the FileDescriptorSet stored in testdata in the text format
+// the same way the standalone command does.
+func loadPlugin(t *testing.T, name string) *protogen.Plugin {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+
+	fds := new(descriptorpb.FileDescriptorSet)
+	require.NoError(t, prototext.Unmarshal(data, fds))
+
+	req, err := plugin.NewRequest(fds, nil, "")
+	require.NoError(t, err)
+
+	p, err := protogen.Options{}.New(req)
+	require.NoError(t, err)
+
+	return p
+}
+
+func findService(t *testing.T, plugin *protogen.Plugin, name string) *protogen.Service {
+	t.Helper()
+
+	for _, f := range plugin.Files {
+		for _, s := range f.Services {
+			if string(s.Desc.Name()) == name {
+				return s
+			}
+		}
+	}
+
+	t.Fatalf("service %q not found", name)
+	return nil
+}
+
+func generateServiceDocument(t *testing.T, fixture, service string) string {
+	t.Helper()
+
+	return generateServiceDocumentWithOptions(t, fixture, service, testOptions())
+}
+
+func generateServiceDocumentWithOptions(t *testing.T, fixture, service string, opts *doc.Options) string {
+	t.Helper()
+
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, doc.NewGenerator(buf, opts).
+		GenerateServiceDocument(findService(t, loadPlugin(t, fixture), service)))
+
+	return buf.String()
+}
+
+// jsonExamples returns the contents of all JSON code blocks of the document.
+func jsonExamples(doc string) []string {
+	matches := jsonBlockRx.FindAllStringSubmatch(doc, -1)
+
+	examples := make([]string, 0, len(matches))
+	for _, m := range matches {
+		examples = append(examples, m[1])
+	}
+
+	return examples
+}
+
+func binaryDumps(doc string) []string {
+	matches := binaryBlockRx.FindAllStringSubmatch(doc, -1)
+
+	dumps := make([]string, 0, len(matches))
+	for _, m := range matches {
+		dumps = append(dumps, m[1])
+	}
+
+	return dumps
+}
+
+func TestGenerator_Oneof(t *testing.T) {
+	t.Parallel()
+
+	out := generateServiceDocument(t, "oneof.textproto", "SearchService")
+
+	require.Contains(t, out, "| *owner*     | exactly one of | Owner of the searched items. |\n")
+	require.Contains(t, out, "| ↳ `userId`  |     string     |")
+	require.Contains(t, out, "| ↳ `groupId` |     int32      |")
+	require.Contains(t, out, "| `limit`     |     int32      |")
+
+	require.Contains(t, out, "*Example with `userId`*\n\n```json\n")
+	require.Contains(t, out, "*Example with `groupId`*\n\n```json\n")
+
+	examples := jsonExamples(out)
+	require.Len(t, examples, 3)
+	require.JSONEq(t, `{"query": "foo", "userId": "foo", "limit": 1073741824}`, examples[0])
+	require.JSONEq(t, `{"query": "foo", "groupId": 1073741824, "limit": 1073741824}`, examples[1])
+	require.JSONEq(t, `{"total": 1073741824}`, examples[2])
+}
+
+func TestGenerator_Examples(t *testing.T) {
+	t.Parallel()
+
+	out := generateServiceDocument(t, "examples.textproto", "UserService")
+
+	examples := jsonExamples(out)
+	require.Len(t, examples, 3)
+	require.JSONEq(t, `{"email": "user@example.com", "age": 42, "tags": ["admin", "staff"], "address": {"city": "Berlin"}}`,
+		examples[0])
+	require.JSONEq(t, `{"id": "u-1"}`, examples[1])
+	require.JSONEq(t, `{"id": "u-2"}`, examples[2])
+}
+
+func TestGenerator_InvalidExample(t *testing.T) {
+	t.Parallel()
+
+	err := doc.NewGenerator(bytes.NewBuffer(nil), testOptions()).
+		GenerateServiceDocument(findService(t, loadPlugin(t, "examples.textproto"), "BadService"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `method Bad: field test.v1.BadRequest.age: invalid example "*forty_two*": `)
+
+	opts := testOptions()
+	opts.InvalidExamples = doc.InvalidExamplesWarn
+
+	out := generateServiceDocumentWithOptions(t, "examples.textproto", "BadService", opts)
+	require.Contains(t, out, "`POST /test.v1.BadService/Bad`\n\n> **Warning:** the example can not be generated: "+
+		`field test.v1.BadRequest.age: invalid example "\*forty\_two\*": `)
+}
+
+func TestGenerator_Streaming(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Warn", func(t *testing.T) {
+		t.Parallel()
+
+		out := generateServiceDocument(t, "streaming.textproto", "EventService")
+		require.Contains(t, out, "### POST `/Watch`\n\n> **Warning:** the method is declared as server streaming RPC.")
+		require.Contains(t, out, "### POST `/Upload`\n\n> **Warning:** the method is declared as client streaming RPC.")
+		require.Contains(t, out, "### POST `/Get`\n\n#### Request\n")
+	})
+
+	t.Run("Skip", func(t *testing.T) {
+		t.Parallel()
+
+		opts := testOptions()
+		opts.Streaming = doc.StreamingSkip
+
+		out := generateServiceDocumentWithOptions(t, "streaming.textproto", "EventService", opts)
+		require.Contains(t, out, "### POST `/Get`")
+		require.NotContains(t, out, "Watch")
+		require.NotContains(t, out, "Upload")
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+
+		opts := testOptions()
+		opts.Streaming = doc.StreamingError
+
+		err := doc.NewGenerator(bytes.NewBuffer(nil), opts).
+			GenerateServiceDocument(findService(t, loadPlugin(t, "streaming.textproto"), "EventService"))
+		require.EqualError(t, err, "method test.v1.EventService.Watch: server streaming RPC is not supported by Twirp")
+	})
+}
+
+func TestGenerator_Links(t *testing.T) {
+	t.Parallel()
+
+	out := generateServiceDocument(t, "models.textproto", "UserService")
+
+	require.Contains(t, out, " [test.v1.User.Status](#testv1userstatus) ")
+	require.Contains(t, out, " array of [test.v1.Role](#testv1role) ")
+
+	for _, c := range []struct {
+		fixture string
+		service string
+	}{
+		{fixture: "models.textproto", service: "UserService"},
+		{fixture: "oneof.textproto", service: "SearchService"},
+		{fixture: "examples.textproto", service: "UserService"},
+		{fixture: "streaming.textproto", service: "EventService"},
+	} {
+		out := generateServiceDocument(t, c.fixture, c.service)
+		anchors := headerAnchors(out)
+
+		links := localLinkRx.FindAllStringSubmatch(out, -1)
+		require.NotEmpty(t, links)
+		for _, l := range links {
+			require.Contains(t, anchors, l[1], "%s: %s: broken link %s", c.fixture, c.service, l[0])
+		}
+	}
+}
+
+func TestGenerator_RepeatedAnchors(t *testing.T) {
+	t.Parallel()
+
+	out := generateServiceDocument(t, "anchors.textproto", "UserService")
+	require.Contains(t, out, "* [test.v1.User](#testv1user)\n* [testv1.User](#testv1user-1)\n")
+	require.Contains(t, out, "| `user`   | [testv1.User](#testv1user-1) |")
+	require.Contains(t, out, "| `legacy` | [test.v1.User](#testv1user)  |")
+	require.Less(t, strings.Index(out, "### test.v1.User\n"), strings.Index(out, "### testv1.User\n"))
+}
+
+var (
+	headerRx      = regexp.MustCompile(`(?m)^#{1,6} (.+)$`)
+	localLinkRx   = regexp.MustCompile(`\]\(#([^)]*)\)`)
+	anchorPunctRx = regexp.MustCompile(`[^\p{L}\p{N}\- _]`)
+)
+
+// headerAnchors returns anchors of the document headers generated the same way GitHub does.
+func headerAnchors(doc string) map[string]bool {
+	anchors := make(map[string]bool)
+	for _, m := range headerRx.FindAllStringSubmatch(doc, -1) {
+		anchor := strings.ReplaceAll(anchorPunctRx.ReplaceAllString(strings.ToLower(m[1]), ""), " ", "-")
+		for i := 0; anchors[anchor]; i++ {
+			anchor = fmt.Sprintf("%s-%d", strings.TrimSuffix(anchor, fmt.Sprintf("-%d", i)), i+1)
+		}
+		anchors[anchor] = true
+	}
+	return anchors
+}
+
+func TestGenerator_WellKnownTypes(t *testing.T) {
+	t.Parallel()
+
+	out := generateServiceDocument(t, "wkt.textproto", "RecordService")
+
+	examples := jsonExamples(out)
+	require.Len(t, examples, 2)
+	require.JSONEq(t, `{}`, examples[0])
+	require.JSONEq(t, `{
+		"createdAt": "2021-09-01T12:30:00Z",
+		"ttl": "13s",
+		"attrs": {"foo": "foo", "bar": {"baz": 42}},
+		"value": "foo",
+		"list": ["foo", 42, true],
+		"mask": "foo,bar.baz",
+		"nothing": {},
+		"name": "foo",
+		"history": ["2021-09-01T12:30:00Z", "2021-09-01T13:30:00Z", "2021-09-01T14:30:00Z"]
+	}`, examples[1])
+
+	for _, label := range []string{
+		"datetime as nullable RFC3339 string",
+		"JSON object",
+		"any JSON value",
+		"JSON array",
+		"field mask as comma separated paths string",
+		"empty object",
+		"null",
+		"array of datetime as nullable RFC3339 string",
+	} {
+		require.Contains(t, out, " "+label+" ")
+	}
+	require.NotContains(t, out, "### Models")
+}
+
+func TestGenerator_EnumExamples(t *testing.T) {
+	t.Parallel()
+
+	out := generateServiceDocument(t, "enums.textproto", "TaskService")
+
+	examples := jsonExamples(out)
+	require.Len(t, examples, 2)
+	require.JSONEq(t, `{
+		"status": "STATUS_NEW",
+		"history": ["STATUS_NEW", "STATUS_DONE", "STATUS_NEW"],
+		"priority": "PRIORITY_HIGH",
+		"levels": ["PRIORITY_HIGH", "PRIORITY_HIGH", "PRIORITY_HIGH"],
+		"stages": ["STAGE_UNSPECIFIED", "STAGE_UNSPECIFIED", "STAGE_UNSPECIFIED"]
+	}`, examples[0])
+	for _, example := range examples {
+		require.NotContains(t, example, "STAGE_DRAFT")
+		require.NotContains(t, example, "ACCESS_")
+	}
+}
+
+func TestGenerator_GenerateDocument(t *testing.T) {
+	t.Parallel()
+
+	plugin := loadPlugin(t, "multi.textproto")
+	services := []*protogen.Service{findService(t, plugin, "UserService"), findService(t, plugin, "GroupService")}
+
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, doc.NewGenerator(buf, testOptions()).GenerateDocument("test.v1", services))
+	out := buf.String()
+
+	require.True(t, strings.HasPrefix(out, "# test.v1\n\n### Services\n\n"+
+		"* [UserService](#testv1userservice): [Get](#post-testv1userserviceget)\n"+
+		"* [GroupService](#testv1groupservice): [Get](#post-testv1groupserviceget), [List](#post-testv1groupservicelist)\n\n"+
+		"### Models\n\n"+
+		"* [test.v1.Kind](#testv1kind)\n"+
+		"* [test.v1.Ref](#testv1ref)\n"+
+		"* [test.v1.User](#testv1user)\n\n---\n\n"+
+		"## test.v1.UserService\n\n"+
+		"Base URL: `https://api.example.com/twirp/test.v1.UserService`\n\n"+
+		"### POST `/test.v1.UserService/Get`\n"), out)
+	require.Contains(t, out, "## test.v1.GroupService\n")
+	require.Contains(t, out, "### POST `/test.v1.GroupService/List`\n")
+
+	require.Equal(t, 1, strings.Count(out, "\n## Models\n"))
+	require.Equal(t, 1, strings.Count(out, "### test.v1.Ref\n"))
+	require.Equal(t, 1, strings.Count(out, "\n## Twirp Errors\n"))
+
+	anchors := headerAnchors(out)
+	for _, l := range localLinkRx.FindAllStringSubmatch(out, -1) {
+		require.Contains(t, anchors, l[1], "broken link %s", l[0])
+	}
+}
+
+func TestGenerator_CommentsEscaping(t *testing.T) {
+	t.Parallel()
+
+	out := generateServiceDocument(t, "comments.textproto", "NoteService")
+	require.Contains(t, out, "*A note with \\*markup\\* and \\<html\\>.*\n\n*\\# Not a header*\n")
+	require.Contains(t, out, "| Either a\\|b or \\`c\\`.<br/>See \\[docs\\](https://example.com). |")
+
+	opts := testOptions()
+	opts.RawComments = true
+
+	out = generateServiceDocumentWithOptions(t, "comments.textproto", "NoteService", opts)
+	require.Contains(t, out, "*A note with *markup* and <html>.*\n\n*# Not a header*\n")
+	require.Contains(t, out, "| Either a\\|b  or `c`.<br/> See [docs](https://example.com). |")
+}
+
+func TestGenerator_CommentsModes(t *testing.T) {
+	t.Parallel()
+
+	out := generateServiceDocument(t, "comments.textproto", "NoteService")
+	require.Contains(t, out, "*Creates the note.*\n\n*Supported markup:*\n\n*\\* lists\n\\* \\`code\\`*\n")
+
+	opts := testOptions()
+	opts.Comments = doc.CommentsPlain
+
+	out = generateServiceDocumentWithOptions(t, "comments.textproto", "NoteService", opts)
+	require.Contains(t, out, "\nCreates the note.\n\nSupported markup:\n\n\\* lists\n\\* \\`code\\`\n")
+
+	opts.Comments = doc.CommentsMarkdown
+
+	out = generateServiceDocumentWithOptions(t, "comments.textproto", "NoteService", opts)
+	require.Contains(t, out, "\nCreates the note.\n\nSupported markup:\n\n* lists\n* `code`\n\n"+
+		"    curl -d '{}' /Create [x][Note]\n\nSee [the label](#testv1label).\n")
+	require.Contains(t, out, "\nA note with *markup* and <html>.\n\n# Not a header\n")
+	require.Contains(t, out, "| See [Label](#testv1label) and [Missing][test.v1.Missing].")
+	require.Contains(t, out, "\nStores the notes, see [the docs][1].\n\n```\n[a][Label]\n```\n\n[1]: https://example.com/docs\n")
+
+	anchors := headerAnchors(out)
+	for _, m := range localLinkRx.FindAllStringSubmatch(out, -1) {
+		require.Contains(t, anchors, m[1])
+	}
+}
+
+func TestGenerator_TrailingComments(t *testing.T) {
+	t.Parallel()
+
+	out := generateServiceDocument(t, "comments.textproto", "NoteService")
+	require.Contains(t, out, "| Plain text. ")
+	require.Contains(t, out, "| Name<br/>of the label.<br/> ")
+	require.Contains(t, out, "### test.v1.Label\n\n*A label.*\n\n*Labels are immutable.*\n")
+	require.NotContains(t, out, "Labels are shared.")
+
+	opts := testOptions()
+	opts.DetachedComments = true
+
+	out = generateServiceDocumentWithOptions(t, "comments.textproto", "NoteService", opts)
+	require.Contains(t, out, "### test.v1.Label\n\n> **Note:**\n>\n> *Labels are shared.*\n\n*A label.*\n")
+}
+
+func TestGenerator_Deprecated(t *testing.T) {
+	t.Parallel()
+
+	out := generateServiceDocument(t, "deprecated.textproto", "ItemService")
+	require.Contains(t, out, "* [OldGet](#post-oldget) `deprecated`\n")
+	require.Contains(t, out, "* [test.v1.LegacyInfo](#testv1legacyinfo) `deprecated`\n")
+	require.Contains(t, out, "### POST `/OldGet`\n\n> **Deprecated:** the method is deprecated and may be removed in the future.\n")
+	require.Contains(t, out, "### test.v1.LegacyInfo\n\n> **Deprecated:** the message is deprecated")
+	require.Contains(t, out, "### test.v1.LegacyKind\n\n> **Deprecated:** the enum is deprecated")
+	require.Contains(t, out, "| ~~`oldName`~~ ")
+	require.Contains(t, out, "| ~~`STATUS_LEGACY`~~  | **Deprecated.** ")
+	require.NotContains(t, out, "### POST `/Get`\n\n> **Deprecated:**")
+
+	for _, example := range jsonExamples(out) {
+		require.JSONEq(t, `{"id": "foo", "oldName": "foo", "status": "STATUS_ACTIVE", "legacy": {"code": "foo"}, `+
+			`"archive": {"code": "foo"}, "kind": "LEGACY_KIND_OLD", "phases": ["PHASE_OLD", "PHASE_OLD", "PHASE_OLD"]}`, example)
+	}
+
+	out = generateServiceDocument(t, "deprecated.textproto", "LegacyService")
+	require.Contains(t, out, "`test.v1.LegacyService`\n\n> **Deprecated:** the service is deprecated")
+}
+
+func TestGenerator_DeprecatedHide(t *testing.T) {
+	t.Parallel()
+
+	opts := testOptions()
+	opts.Deprecated = doc.DeprecatedHide
+
+	out := generateServiceDocumentWithOptions(t, "deprecated.textproto", "ItemService", opts)
+	require.NotContains(t, out, "OldGet")
+	require.NotContains(t, out, "oldName")
+	require.NotContains(t, out, "legacy")
+	require.NotContains(t, out, "LegacyInfo")
+	require.NotContains(t, out, "archive")
+	require.NotContains(t, out, "LegacyKind")
+	require.NotContains(t, out, "STATUS_LEGACY")
+	require.NotContains(t, out, "PHASE_OLD")
+	require.NotContains(t, out, "deprecated")
+
+	for _, example := range jsonExamples(out) {
+		require.JSONEq(t, `{"id": "foo", "status": "STATUS_ACTIVE", `+
+			`"phases": ["PHASE_UNSPECIFIED", "PHASE_UNSPECIFIED", "PHASE_UNSPECIFIED"]}`, example)
+	}
+
+	plugin := loadPlugin(t, "deprecated.textproto")
+	services := []*protogen.Service{findService(t, plugin, "ItemService"), findService(t, plugin, "LegacyService")}
+
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, doc.NewGenerator(buf, opts).GenerateDocument("test.v1", services))
+	require.NotContains(t, buf.String(), "LegacyService")
+}
+
+func TestGenerator_Internal(t *testing.T) {
+	t.Parallel()
+
+	out := generateServiceDocument(t, "internal.textproto", "AccountService")
+	require.Contains(t, out, "### Methods\n\n* [Get](#post-get)\n\n### Models\n\n* [test.v1.Role](#testv1role)\n\n---\n")
+	require.NotContains(t, out, "Debug")
+	require.NotContains(t, out, "Purge")
+	require.NotContains(t, out, "secret")
+	require.NotContains(t, out, "ROLE_ROOT")
+	require.NotContains(t, out, "@internal")
+
+	for _, example := range jsonExamples(out) {
+		require.JSONEq(t, `{"id": "foo", "role": "ROLE_USER"}`, example)
+	}
+
+	plugin := loadPlugin(t, "internal.textproto")
+	services := []*protogen.Service{findService(t, plugin, "AccountService"), findService(t, plugin, "AdminService")}
+
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, doc.NewGenerator(buf, testOptions()).GenerateDocument("test.v1", services))
+	require.NotContains(t, buf.String(), "AdminService")
+}
+
+func TestGenerator_ProtobufEncoding(t *testing.T) {
+	t.Parallel()
+
+	opts := testOptions()
+	opts.Encodings = []string{doc.ContentJSON, doc.ContentProtobuf}
+
+	out := generateServiceDocumentWithOptions(t, "oneof.textproto", "SearchService", opts)
+	require.Contains(t, out, "**JSON** `Content-Type: application/json`\n")
+	require.Contains(t, out, "**Protobuf** `Content-Type: application/protobuf`\n")
+
+	examples := jsonExamples(out)
+	dumps := binaryDumps(out)
+	require.Len(t, dumps, len(examples))
+
+	method := findService(t, loadPlugin(t, "oneof.textproto"), "SearchService").Methods[0]
+	descs := []protoreflect.MessageDescriptor{method.Input.Desc, method.Input.Desc, method.Output.Desc}
+	for i, desc := range descs {
+		b, err := hex.DecodeString(strings.NewReplacer(" ", "", "\n", "").Replace(dumps[i]))
+		require.NoError(t, err)
+
+		m := dynamicpb.NewMessage(desc)
+		require.NoError(t, proto.Unmarshal(b, m))
+		j, err := protojson.Marshal(m)
+		require.NoError(t, err)
+		require.JSONEq(t, examples[i], string(j))
+	}
+
+	opts.Encodings = []string{doc.ContentProtobuf}
+	opts.BinaryDump = doc.BinaryDumpBase64
+
+	out = generateServiceDocumentWithOptions(t, "oneof.textproto", "SearchService", opts)
+	require.Empty(t, jsonExamples(out))
+	require.NotContains(t, out, "application/json")
+	require.Contains(t, binaryDumps(out), "CICAgIAE")
+}
+
+func TestGenerator_CurlSamples(t *testing.T) {
+	t.Parallel()
+
+	out := generateServiceDocument(t, "oneof.textproto", "SearchService")
+	require.NotContains(t, out, "curl")
+
+	opts := testOptions()
+	opts.Samples = []string{doc.SampleCurl}
+	opts.Headers = []string{"Authorization: Bearer $TOKEN"}
+
+	out = generateServiceDocumentWithOptions(t, "oneof.textproto", "SearchService", opts)
+	require.Contains(t, out, "#### Samples\n\n```sh\n"+
+		"curl -X POST 'https://api.example.com/twirp/test.v1.SearchService/Search' \\\n"+
+		"  -H 'Content-Type: application/json' \\\n"+
+		"  -H \"Authorization: Bearer $TOKEN\" \\\n"+
+		"  -d '{\"query\":\"foo\",\"userId\":\"foo\",\"limit\":1073741824}'\n```\n")
+}
+
+func TestGenerator_ClientSamples(t *testing.T) {
+	t.Parallel()
+
+	opts := testOptions()
+	opts.Samples = []string{doc.SampleGo, doc.SampleTypeScript, doc.SamplePython, doc.SampleCurl}
+
+	out := generateServiceDocumentWithOptions(t, "oneof.textproto", "SearchService", opts)
+	require.Contains(t, out, "#### Samples\n\n**Go**\n\n```go\npackage main\n\n"+
+		"import (\n\t\"context\"\n\t\"log\"\n\t\"net/http\"\n\n"+
+		"\t\"google.golang.org/protobuf/encoding/protojson\"\n\n\ttestv1 \"example.com/test/v1\"\n)\n\n"+
+		"func main() {\n\tctx := context.Background()\n"+
+		"\tclient := testv1.NewSearchServiceJSONClient(\"https://api.example.com\", http.DefaultClient)\n\n"+
+		"\treq := new(testv1.SearchRequest)\n"+
+		"\tif err := protojson.Unmarshal([]byte(`{\"query\":\"foo\",\"userId\":\"foo\",\"limit\":1073741824}`), req); err != nil {\n")
+	require.Contains(t, out, "\tresp, err := client.Search(ctx, req)\n")
+	require.Contains(t, out, "**TypeScript**\n\n```ts\n"+
+		"const response = await fetch(\"https://api.example.com/twirp/test.v1.SearchService/Search\", {\n")
+	require.Contains(t, out, "  body: JSON.stringify({\"query\":\"foo\",\"userId\":\"foo\",\"limit\":1073741824}),\n")
+	require.Contains(t, out, "**Python**\n\n```python\nimport requests\n")
+	require.Contains(t, out, "    data='{\"query\":\"foo\",\"userId\":\"foo\",\"limit\":1073741824}',\n")
+	require.Contains(t, out, "**curl**\n\n```sh\ncurl -X POST ")
+
+	opts.BaseURL = "https://api.example.com/rpc"
+	opts.Samples = []string{doc.SampleGo}
+
+	out = generateServiceDocumentWithOptions(t, "oneof.textproto", "SearchService", opts)
+	require.Contains(t, out, "#### Samples\n\n```go\n")
+	require.Contains(t, out, "\t\"github.com/twitchtv/twirp\"\n")
+	require.Contains(t, out, "NewSearchServiceJSONClient(\"https://api.example.com/rpc\", http.DefaultClient, "+
+		"twirp.WithClientPathPrefix(\"\"))\n")
+}
+
+func TestGenerator_MethodErrors(t *testing.T) {
+	t.Parallel()
+
+	out := generateServiceDocument(t, "errors.textproto", "UserService")
+	require.Contains(t, out, "### POST `/Get`\n\n*Returns the user.*\n\n#### Request\n")
+	require.Contains(t, out, "#### Errors\n\n"+
+		"| Twirp Error Code    | HTTP Status | Description             |\n"+
+		"|:--------------------|:-----------:|:------------------------|\n"+
+		"| `invalid_argument`  |    `400`    | the user id is empty    |\n"+
+		"| `not_found`         |    `404`    | the user does not exist |\n"+
+		"| `permission_denied` |    `403`    |                         |\n")
+	require.Contains(t, out, "**Error meta**\n\n"+
+		"| Twirp Error Code   | Meta Key   | Description                   |\n"+
+		"|:-------------------|:-----------|:------------------------------|\n"+
+		"| `invalid_argument` | `argument` | the name of the invalid field |\n"+
+		"| `not_found`        | `user_id`  | the id of the missing user    |\n")
+	require.Contains(t, out, "Example of the `invalid_argument` error response body:\n\n```json\n")
+	examples := jsonExamples(out)
+	require.Len(t, examples, 3)
+	require.JSONEq(t, `{"code": "invalid_argument", "msg": "the user id is empty", "meta": {"argument": "the name of the invalid field"}}`,
+		examples[2])
+	require.NotContains(t, out, "@error")
+	require.NotContains(t, out, "@meta")
+
+	err := doc.NewGenerator(bytes.NewBuffer(nil), testOptions()).
+		GenerateServiceDocument(findService(t, loadPlugin(t, "errors.textproto"), "BadService"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `method test.v1.BadService.Get: unknown Twirp error code "nope"`)
+
+	err = doc.NewGenerator(bytes.NewBuffer(nil), testOptions()).
+		GenerateServiceDocument(findService(t, loadPlugin(t, "errors.textproto"), "MetaService"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `method test.v1.MetaService.Get: meta key "user_id" of undeclared Twirp error "not_found"`)
+
+	err = doc.NewGenerator(bytes.NewBuffer(nil), testOptions()).
+		GenerateServiceDocument(findService(t, loadPlugin(t, "errors.textproto"), "EmptyService"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `method test.v1.EmptyService.Get: invalid @error directive without the error code`)
+}
+
+func TestGenerator_ErrorsModes(t *testing.T) {
+	t.Parallel()
+
+	opts := testOptions()
+	opts.Errors = doc.ErrorsCollapse
+
+	out := generateServiceDocumentWithOptions(t, "errors.textproto", "UserService", opts)
+	require.Contains(t, out, "## Twirp Errors\n\n<details>\n<summary>Twirp error codes</summary>\n\n[Official documentation]")
+	require.True(t, strings.HasSuffix(out, "|\n\n</details>\n"))
+
+	opts.Errors = doc.ErrorsOmit
+
+	out = generateServiceDocumentWithOptions(t, "errors.textproto", "UserService", opts)
+	require.NotContains(t, out, "## Twirp Errors")
+	require.Contains(t, out, "#### Errors\n")
+
+	opts.Errors = doc.ErrorsLink
+	opts.ErrorsURL = "../twirp_errors.md"
+
+	out = generateServiceDocumentWithOptions(t, "errors.textproto", "UserService", opts)
+	require.True(t, strings.HasSuffix(out, "## Twirp Errors\n\nSee [Twirp error codes](../twirp_errors.md).\n"))
+}
+
+func TestGenerator_FixedKinds(t *testing.T) {
+	t.Parallel()
+
+	out := generateServiceDocument(t, "fixed.textproto", "CounterService")
+	require.Contains(t, out, "| `a`   |              uint32              |")
+	require.Contains(t, out, "| `b`   |     uint64 as numeric string     |")
+	require.Contains(t, out, "| `d`   | array of int64 as numeric string |")
+	require.JSONEq(t, `{"a": 1073741824, "b": "1073741824", "c": 1073741824, "d": ["1073741824", "1073741824", "1073741824"]}`,
+		jsonExamples(out)[0])
+}
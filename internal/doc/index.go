@@ -0,0 +1,95 @@
+package doc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+
+	md "github.com/albenik/twirp-doc-gen/internal/markdown"
+)
+
+// IndexDocument describes the generated document listed in the index.
+type IndexDocument struct {
+	// Path is the slash separated path of the document relative to the index document.
+	Path string
+	// Services are the services described by the document.
+	Services []*protogen.Service
+	// Combined is true for the document generated by GenerateDocument.
+	Combined bool
+}
+
+type indexService struct {
+	path     string
+	service  *Service
+	combined bool
+}
+
+// GenerateIndex writes the index document linking every service and method of the documents.
+// The services are grouped by proto package, packages and services are sorted by name.
+func (g *Generator) GenerateIndex(title string, docs []*IndexDocument) error {
+	packages := make(map[string][]*indexService)
+	for _, d := range docs {
+		model, err := g.BuildDocument(d.Path, d.Services, d.Combined)
+		if err != nil {
+			return fmt.Errorf("%s: %w", d.Path, err)
+		}
+		for _, s := range model.Services {
+			packages[s.Package] = append(packages[s.Package], &indexService{path: d.Path, service: s, combined: model.Combined})
+		}
+	}
+
+	names := make([]string, 0, len(packages))
+	for pkg := range packages {
+		names = append(names, pkg)
+	}
+	sort.Strings(names)
+
+	g.begin(nil)
+	g.doc.Append(md.TH1(title))
+
+	for _, pkg := range names {
+		services := packages[pkg]
+		sort.Slice(services, func(i, j int) bool {
+			return services[i].service.Name < services[j].service.Name
+		})
+
+		g.doc.Append(md.H2(md.Code(pkg)))
+
+		for _, s := range services {
+			g.printIndexService(s)
+		}
+	}
+
+	return g.doc.Generate(g.writer)
+}
+
+// printIndexService prints the service with the links to its methods, the services of the combined documents
+// are linked by their anchors.
+func (g *Generator) printIndexService(s *indexService) {
+	serviceHref := s.path
+	if s.combined {
+		serviceHref += s.service.Anchor
+	}
+	g.doc.Append(md.H3(md.Link(serviceHref, s.service.Name), deprecatedBadge(s.service.Deprecated)))
+
+	if summary := commentSummary(s.service.Comments); summary != "" {
+		g.doc.Append(md.P(g.text(summary)))
+	}
+
+	items := make([]md.Block, 0, len(s.service.Methods))
+	for _, m := range s.service.Methods {
+		item := md.G(md.Link(s.path+m.Anchor, m.Name), deprecatedBadge(m.Deprecated))
+		if summary := commentSummary(m.Comments); summary != "" {
+			item = md.G(item, md.T(" — "), g.text(summary))
+		}
+		items = append(items, item)
+	}
+	g.doc.Append(md.UL(items...))
+}
+
+// commentSummary returns the first line of the leading or trailing comment.
+func commentSummary(c *Comments) string {
+	return strings.SplitN(commentsText(c), "\n", 2)[0] //nolint:gomnd
+}
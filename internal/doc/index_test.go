@@ -0,0 +1,46 @@
+package doc_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/compiler/protogen"
+
+	"github.com/albenik/twirp-doc-gen/internal/doc"
+)
+
+func TestGenerator_GenerateIndex(t *testing.T) {
+	t.Parallel()
+
+	plugin := loadPlugin(t, "multi.textproto")
+	users := findService(t, plugin, "UserService")
+	groups := findService(t, plugin, "GroupService")
+	events := findService(t, loadPlugin(t, "streaming.textproto"), "EventService")
+
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, doc.NewGenerator(buf, testOptions()).GenerateIndex("API Reference", []*doc.IndexDocument{
+		{Path: "test/v1/UserService.md", Services: []*protogen.Service{users}},
+		{Path: "test/v1/EventService.md", Services: []*protogen.Service{events}},
+		{Path: "test/v1/GroupService.md", Services: []*protogen.Service{groups}},
+	}))
+	require.Equal(t, "# API Reference\n\n"+
+		"## `test.v1`\n\n"+
+		"### [EventService](test/v1/EventService.md)\n\n"+
+		"* [Get](test/v1/EventService.md#post-get)\n"+
+		"* [Watch](test/v1/EventService.md#post-watch)\n"+
+		"* [Upload](test/v1/EventService.md#post-upload)\n\n"+
+		"### [GroupService](test/v1/GroupService.md)\n\n"+
+		"Manages groups of users.\n\n"+
+		"* [Get](test/v1/GroupService.md#post-get)\n"+
+		"* [List](test/v1/GroupService.md#post-list) — Lists groups\n\n"+
+		"### [UserService](test/v1/UserService.md)\n\n"+
+		"* [Get](test/v1/UserService.md#post-get)\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, doc.NewGenerator(buf, testOptions()).GenerateIndex("API Reference", []*doc.IndexDocument{
+		{Path: "test/v1/test.v1.md", Services: []*protogen.Service{users, groups}, Combined: true},
+	}))
+	require.Contains(t, buf.String(), "### [GroupService](test/v1/test.v1.md#testv1groupservice)\n")
+	require.Contains(t, buf.String(), "* [List](test/v1/test.v1.md#post-testv1groupservicelist) — Lists groups\n")
+}
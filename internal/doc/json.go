@@ -0,0 +1,82 @@
+package doc
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// exampleJSON is the JSON representation of the example, the JSON encoding is embedded as is.
+type exampleJSON struct {
+	Variant []string        `json:"variant,omitempty"`
+	JSON    json.RawMessage `json:"json,omitempty"`
+	Binary  string          `json:"binary,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e *Example) MarshalJSON() ([]byte, error) {
+	v := &exampleJSON{Variant: e.Variant, Binary: e.Binary}
+	if e.JSON != "" {
+		v.JSON = json.RawMessage(e.JSON)
+	}
+	if e.Err != nil {
+		v.Error = e.Err.Error()
+	}
+	return json.Marshal(v)
+}
+
+// GenerateServiceJSON writes the JSON description of the service, the documentation model in the JSON encoding.
+func (g *Generator) GenerateServiceJSON(service *protogen.Service) error {
+	d, err := g.BuildDocument(string(service.Desc.Name()), []*protogen.Service{service}, false)
+	if err != nil {
+		return err
+	}
+	return g.writeJSON(d)
+}
+
+// GenerateJSON writes the combined JSON description of several services.
+func (g *Generator) GenerateJSON(title string, services []*protogen.Service) error {
+	d, err := g.BuildDocument(title, services, true)
+	if err != nil {
+		return err
+	}
+	return g.writeJSON(d)
+}
+
+func (g *Generator) writeJSON(d *Document) error {
+	// the request and response examples fail the generation the same way they fail the Markdown document
+	if g.opts.InvalidExamples != InvalidExamplesWarn {
+		for _, s := range d.Services {
+			for _, m := range s.Methods {
+				if err := examplesError(m); err != nil {
+					if d.Combined {
+						return fmt.Errorf("service %s: method %s: %w", s.FullName, m.Name, err)
+					}
+					return fmt.Errorf("method %s: %w", m.Name, err)
+				}
+			}
+		}
+	}
+
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = g.writer.Write(append(data, '\n'))
+	return err
+}
+
+// examplesError returns the first failure of the request and response examples generation.
+func examplesError(m *Method) error {
+	for _, message := range []*Message{m.Request, m.Response} {
+		for _, e := range message.Examples {
+			if e.Err != nil {
+				return e.Err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package doc_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/compiler/protogen"
+
+	"github.com/albenik/twirp-doc-gen/internal/doc"
+)
+
+func generateServiceJSON(t *testing.T, fixture, service string, opts *doc.Options) map[string]interface{} {
+	t.Helper()
+
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, doc.NewGenerator(buf, opts).GenerateServiceJSON(findService(t, loadPlugin(t, fixture), service)))
+
+	var v map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &v))
+	return v
+}
+
+func TestGenerator_GenerateServiceJSON(t *testing.T) {
+	t.Parallel()
+
+	v := generateServiceJSON(t, "models.textproto", "UserService", testOptions())
+	require.Equal(t, "UserService", v["title"])
+	require.Equal(t, false, v["combined"])
+
+	service := v["services"].([]interface{})[0].(map[string]interface{})
+	require.Equal(t, "test.v1.UserService", service["fullName"])
+	require.Equal(t, testBaseURL+"/test.v1.UserService", service["baseUrl"])
+
+	method := service["methods"].([]interface{})[0].(map[string]interface{})
+	require.Equal(t, "/test.v1.UserService/Get_User", method["path"])
+	require.Equal(t, testBaseURL+"/test.v1.UserService/Get_User", method["url"])
+	require.NotContains(t, method, "anchor")
+	require.NotContains(t, method, "headingPath")
+
+	request := method["request"].(map[string]interface{})
+	require.Equal(t, []interface{}{map[string]interface{}{"json": map[string]interface{}{"id": "foo"}}}, request["examples"])
+
+	fields := method["response"].(map[string]interface{})["fields"].([]interface{})
+	require.Equal(t, map[string]interface{}{
+		"name":     "roles",
+		"jsonName": "roles",
+		"type": map[string]interface{}{
+			"label":    "test.v1.Role",
+			"kind":     "enum",
+			"fullName": "test.v1.Role",
+			"model":    "test.v1.Role",
+			"repeated": true,
+		},
+		"comments": map[string]interface{}{},
+	}, fields[2])
+	require.Equal(t, map[string]interface{}{
+		"label":  "map",
+		"kind":   "message",
+		"mapKey": map[string]interface{}{"label": "string", "kind": "string"},
+		"mapValue": map[string]interface{}{
+			"label":    "test.v1.User_Profile",
+			"kind":     "message",
+			"fullName": "test.v1.User_Profile",
+			"model":    "test.v1.User_Profile",
+		},
+	}, fields[4].(map[string]interface{})["type"])
+
+	require.Len(t, v["messages"], 1)
+	require.Len(t, v["enums"], 2)
+	require.NotContains(t, v["enums"].([]interface{})[0], "anchor")
+
+	codes := v["errorCodes"].([]interface{})
+	require.Len(t, codes, 18)
+	require.Equal(t, map[string]interface{}{
+		"code":        "not_found",
+		"httpStatus":  float64(404),
+		"description": "Some requested entity was not found.",
+	}, codes[6])
+
+	opts := testOptions()
+	opts.Errors = doc.ErrorsOmit
+	require.NotContains(t, generateServiceJSON(t, "models.textproto", "UserService", opts), "errorCodes")
+}
+
+func TestGenerator_GenerateServiceJSON_KnownTypes(t *testing.T) {
+	t.Parallel()
+
+	v := generateServiceJSON(t, "wkt.textproto", "RecordService", testOptions())
+	method := v["services"].([]interface{})[0].(map[string]interface{})["methods"].([]interface{})[0].(map[string]interface{})
+
+	labels := make(map[string]interface{})
+	for _, f := range method["response"].(map[string]interface{})["fields"].([]interface{}) {
+		field := f.(map[string]interface{})
+		labels[field["name"].(string)] = field["type"].(map[string]interface{})["label"]
+	}
+	require.Equal(t, "datetime as nullable RFC3339 string", labels["created_at"])
+	require.Equal(t, "duration as nullable string", labels["ttl"])
+}
+
+func TestGenerator_GenerateServiceJSON_Errors(t *testing.T) {
+	t.Parallel()
+
+	v := generateServiceJSON(t, "errors.textproto", "UserService", testOptions())
+	method := v["services"].([]interface{})[0].(map[string]interface{})["methods"].([]interface{})[0].(map[string]interface{})
+
+	require.Equal(t, map[string]interface{}{"leading": " Returns the user.\n\n"}, method["comments"])
+	require.Equal(t, map[string]interface{}{
+		"code":        "invalid_argument",
+		"httpStatus":  float64(400),
+		"description": "the user id is empty",
+		"meta":        []interface{}{map[string]interface{}{"key": "argument", "description": "the name of the invalid field"}},
+	}, method["errors"].([]interface{})[0])
+	require.Equal(t, map[string]interface{}{"code": "permission_denied", "httpStatus": float64(403)},
+		method["errors"].([]interface{})[2])
+}
+
+func TestGenerator_GenerateServiceJSON_InvalidExample(t *testing.T) {
+	t.Parallel()
+
+	err := doc.NewGenerator(bytes.NewBuffer(nil), testOptions()).
+		GenerateServiceJSON(findService(t, loadPlugin(t, "examples.textproto"), "BadService"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `method Bad: field test.v1.BadRequest.age: invalid example "*forty_two*": `)
+
+	opts := testOptions()
+	opts.InvalidExamples = doc.InvalidExamplesWarn
+
+	v := generateServiceJSON(t, "examples.textproto", "BadService", opts)
+	method := v["services"].([]interface{})[0].(map[string]interface{})["methods"].([]interface{})[0].(map[string]interface{})
+	example := method["request"].(map[string]interface{})["examples"].([]interface{})[0].(map[string]interface{})
+	require.Contains(t, example["error"], `field test.v1.BadRequest.age: invalid example "*forty_two*": `)
+	require.NotContains(t, example, "json")
+}
+
+func TestGenerator_GenerateJSON(t *testing.T) {
+	t.Parallel()
+
+	plugin := loadPlugin(t, "multi.textproto")
+
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, doc.NewGenerator(buf, testOptions()).GenerateJSON("API Reference", []*protogen.Service{
+		findService(t, plugin, "UserService"),
+		findService(t, plugin, "GroupService"),
+	}))
+
+	var v map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &v))
+	require.Equal(t, "API Reference", v["title"])
+	require.Equal(t, true, v["combined"])
+	require.Len(t, v["services"], 2)
+	require.Len(t, v["messages"], 2)
+
+	method := v["services"].([]interface{})[1].(map[string]interface{})["methods"].([]interface{})[1].(map[string]interface{})
+	require.Equal(t, "/test.v1.GroupService/List", method["path"])
+	require.NotContains(t, method, "headingPath")
+}
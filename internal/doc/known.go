@@ -17,6 +17,12 @@ const (
 	googleProtobufDoubleValue = "google.protobuf.DoubleValue"
 	googleProtobufTimestamp   = "google.protobuf.Timestamp"
 	googleProtobufDuration    = "google.protobuf.Duration"
+	googleProtobufStruct      = "google.protobuf.Struct"
+	googleProtobufValue       = "google.protobuf.Value"
+	googleProtobufListValue   = "google.protobuf.ListValue"
+	googleProtobufNullValue   = "google.protobuf.NullValue"
+	googleProtobufFieldMask   = "google.protobuf.FieldMask"
+	googleProtobufEmpty       = "google.protobuf.Empty"
 )
 
 // Data types mappings
@@ -24,17 +30,21 @@ const (
 // https://developers.google.com/protocol-buffers/docs/proto3#json
 var (
 	protoKindTypes = map[protoreflect.Kind]string{
-		protoreflect.Int32Kind:  "int32",
-		protoreflect.Sint32Kind: "int32",
-		protoreflect.Uint32Kind: "uint32",
-		protoreflect.Int64Kind:  "int64 as numeric string",
-		protoreflect.Sint64Kind: "int64 as numeric string",
-		protoreflect.Uint64Kind: "uint64 as numeric string",
-		protoreflect.FloatKind:  "float",
-		protoreflect.DoubleKind: "double",
-		protoreflect.BoolKind:   "bool",
-		protoreflect.StringKind: "string",
-		protoreflect.BytesKind:  "bytes as base64 string",
+		protoreflect.Int32Kind:    "int32",
+		protoreflect.Sint32Kind:   "int32",
+		protoreflect.Uint32Kind:   "uint32",
+		protoreflect.Int64Kind:    "int64 as numeric string",
+		protoreflect.Sint64Kind:   "int64 as numeric string",
+		protoreflect.Uint64Kind:   "uint64 as numeric string",
+		protoreflect.Sfixed32Kind: "int32",
+		protoreflect.Fixed32Kind:  "uint32",
+		protoreflect.Sfixed64Kind: "int64 as numeric string",
+		protoreflect.Fixed64Kind:  "uint64 as numeric string",
+		protoreflect.FloatKind:    "float",
+		protoreflect.DoubleKind:   "double",
+		protoreflect.BoolKind:     "bool",
+		protoreflect.StringKind:   "string",
+		protoreflect.BytesKind:    "bytes as base64 string",
 	}
 
 	protoKnownTypeLabels = map[protoreflect.FullName]string{
@@ -50,5 +60,59 @@ var (
 		googleProtobufDoubleValue: "nullable double",
 		googleProtobufTimestamp:   "datetime as nullable RFC3339 string",
 		googleProtobufDuration:    "duration as nullable string",
+		googleProtobufStruct:      "JSON object",
+		googleProtobufValue:       "any JSON value",
+		googleProtobufListValue:   "JSON array",
+		googleProtobufNullValue:   "null",
+		googleProtobufFieldMask:   "field mask as comma separated paths string",
+		googleProtobufEmpty:       "empty object",
+	}
+)
+
+// OpenAPI data types mappings
+// https://spec.openapis.org/oas/v3.1.0#data-types
+var (
+	protoKindSchemas = map[protoreflect.Kind]*openAPISchema{
+		protoreflect.Int32Kind:    {Type: "integer", Format: "int32"},
+		protoreflect.Sint32Kind:   {Type: "integer", Format: "int32"},
+		protoreflect.Uint32Kind:   {Type: "integer", Format: "uint32"},
+		protoreflect.Int64Kind:    {Type: "string", Format: "int64"},
+		protoreflect.Sint64Kind:   {Type: "string", Format: "int64"},
+		protoreflect.Uint64Kind:   {Type: "string", Format: "uint64"},
+		protoreflect.Sfixed32Kind: {Type: "integer", Format: "int32"},
+		protoreflect.Fixed32Kind:  {Type: "integer", Format: "uint32"},
+		protoreflect.Sfixed64Kind: {Type: "string", Format: "int64"},
+		protoreflect.Fixed64Kind:  {Type: "string", Format: "uint64"},
+		protoreflect.FloatKind:    {Type: "number", Format: "float"},
+		protoreflect.DoubleKind:   {Type: "number", Format: "double"},
+		protoreflect.BoolKind:     {Type: "boolean"},
+		protoreflect.StringKind:   {Type: "string"},
+		protoreflect.BytesKind:    {Type: "string", Format: "byte"},
+	}
+
+	protoKnownTypeSchemas = map[protoreflect.FullName]*openAPISchema{
+		googleProtobufAny: {
+			Type:                 "object",
+			Properties:           map[string]*openAPISchema{"@type": {Type: "string"}},
+			Required:             []string{"@type"},
+			AdditionalProperties: true,
+		},
+		googleProtobufStringValue: {Type: []string{"string", "null"}},
+		googleProtobufBytesValue:  {Type: []string{"string", "null"}, Format: "byte"},
+		googleProtobufBoolValue:   {Type: []string{"boolean", "null"}},
+		googleProtobufInt32Value:  {Type: []string{"integer", "null"}, Format: "int32"},
+		googleProtobufInt64Value:  {Type: []string{"string", "null"}, Format: "int64"},
+		googleProtobufUInt32Value: {Type: []string{"integer", "null"}, Format: "uint32"},
+		googleProtobufUInt64Value: {Type: []string{"string", "null"}, Format: "uint64"},
+		googleProtobufFloatValue:  {Type: []string{"number", "null"}, Format: "float"},
+		googleProtobufDoubleValue: {Type: []string{"number", "null"}, Format: "double"},
+		googleProtobufTimestamp:   {Type: []string{"string", "null"}, Format: "date-time"},
+		googleProtobufDuration:    {Type: []string{"string", "null"}, Pattern: `^-?\d+(\.\d+)?s$`},
+		googleProtobufStruct:      {Type: "object", AdditionalProperties: true},
+		googleProtobufValue:       {},
+		googleProtobufListValue:   {Type: "array", Items: &openAPISchema{}},
+		googleProtobufNullValue:   {Type: "null"},
+		googleProtobufFieldMask:   {Type: "string"},
+		googleProtobufEmpty:       {Type: "object"},
 	}
 )
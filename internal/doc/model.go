@@ -0,0 +1,599 @@
+package doc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	md "github.com/albenik/twirp-doc-gen/internal/markdown"
+)
+
+// Document is the format-neutral documentation model of the services generated into the single document.
+// The elements hidden by the options are not included. The anchors and the heading paths are the details
+// of the Markdown and HTML layouts, they are omitted from the JSON encoding.
+type Document struct {
+	Title string `json:"title"`
+	// Combined is true for the document of several services sharing the models.
+	Combined bool       `json:"combined"`
+	Services []*Service `json:"services"`
+	// Messages and Enums are the models referenced by the fields of the request and response messages,
+	// sorted by full name.
+	Messages []*Message `json:"messages,omitempty"`
+	Enums    []*Enum    `json:"enums,omitempty"`
+	// ErrorCodes are the standard Twirp error codes, omitted with the errors=omit option.
+	ErrorCodes []*ErrorCode `json:"errorCodes,omitempty"`
+}
+
+// Service is the documented Twirp service.
+type Service struct {
+	Name       string    `json:"name"`
+	FullName   string    `json:"fullName"`
+	Package    string    `json:"package"`
+	Anchor     string    `json:"-"`
+	BaseURL    string    `json:"baseUrl"`
+	Comments   *Comments `json:"comments"`
+	Deprecated bool      `json:"deprecated,omitempty"`
+	Methods    []*Method `json:"methods"`
+}
+
+// Method is the documented Twirp method.
+type Method struct {
+	Name string `json:"name"`
+	// Path is the Twirp route of the method, e.g. "/pkg.Service/Method".
+	Path string `json:"path"`
+	// HeadingPath is the path shown in the method heading, the full Path in the combined documents only.
+	HeadingPath string `json:"-"`
+	URL         string `json:"url"`
+	Anchor      string `json:"-"`
+	// Streaming is the kind of the streaming method, empty for the unary one.
+	Streaming  string       `json:"streaming,omitempty"`
+	Comments   *Comments    `json:"comments"`
+	Deprecated bool         `json:"deprecated,omitempty"`
+	Request    *Message     `json:"request"`
+	Response   *Message     `json:"response"`
+	Errors     []*ErrorSpec `json:"errors,omitempty"`
+	Samples    []*Sample    `json:"samples,omitempty"`
+}
+
+// Message is the documented message, the request, the response or the model.
+type Message struct {
+	Name     string `json:"name"`
+	FullName string `json:"fullName"`
+	// Anchor is empty unless the message is the documented model.
+	Anchor     string    `json:"-"`
+	Comments   *Comments `json:"comments"`
+	Deprecated bool      `json:"deprecated,omitempty"`
+	// Fields are in the declaration order, the oneof members refer to their Oneofs.
+	Fields   []*Field   `json:"fields"`
+	Oneofs   []*Oneof   `json:"oneofs,omitempty"`
+	Examples []*Example `json:"examples,omitempty"`
+}
+
+// Oneof is the oneof of the message, synthetic oneofs of the proto3 optional fields are omitted.
+type Oneof struct {
+	Name     string    `json:"name"`
+	Comments *Comments `json:"comments"`
+}
+
+// Field is the field of the message.
+type Field struct {
+	Name       string    `json:"name"`
+	JSONName   string    `json:"jsonName"`
+	Type       *Type     `json:"type"`
+	Comments   *Comments `json:"comments"`
+	Deprecated bool      `json:"deprecated,omitempty"`
+	// Oneof is the name of the oneof the field belongs to.
+	Oneof string `json:"oneof,omitempty"`
+}
+
+// Type is the resolved type of the field.
+type Type struct {
+	// Label is the human label of the scalar or well-known type, the full name of the model or "map".
+	Label string `json:"label"`
+	// Kind is the protobuf kind of the field, e.g. "int64", "message" or "enum".
+	Kind string `json:"kind"`
+	// FullName is the full name of the message or enum type, empty for the scalar and map types.
+	FullName string `json:"fullName,omitempty"`
+	// Model is the full name of the documented message or enum.
+	Model    string `json:"model,omitempty"`
+	Anchor   string `json:"-"`
+	Repeated bool   `json:"repeated,omitempty"`
+	MapKey   *Type  `json:"mapKey,omitempty"`
+	MapValue *Type  `json:"mapValue,omitempty"`
+}
+
+// Enum is the documented enum.
+type Enum struct {
+	Name       string       `json:"name"`
+	FullName   string       `json:"fullName"`
+	Anchor     string       `json:"-"`
+	Comments   *Comments    `json:"comments"`
+	Deprecated bool         `json:"deprecated,omitempty"`
+	Values     []*EnumValue `json:"values"`
+}
+
+// EnumValue is the value of the enum.
+type EnumValue struct {
+	Name       string    `json:"name"`
+	Number     int32     `json:"number"`
+	Comments   *Comments `json:"comments"`
+	Deprecated bool      `json:"deprecated,omitempty"`
+}
+
+// Example is the example of the message in the documented encodings.
+type Example struct {
+	// Variant lists the JSON names of the oneof fields set by the example if the message has several variants.
+	Variant []string
+	JSON    string
+	// Binary is the dump of the protobuf encoding.
+	Binary string
+	// Err is the failure of the example generation.
+	Err error
+
+	msg protoreflect.Message
+}
+
+// Sample is the method call sample.
+type Sample struct {
+	Language string `json:"language"`
+	Label    string `json:"label"`
+	Syntax   string `json:"syntax"`
+	Code     string `json:"code"`
+}
+
+// Comments are the comments of the element without the generator directives.
+type Comments struct {
+	Leading  string   `json:"leading,omitempty"`
+	Trailing string   `json:"trailing,omitempty"`
+	Detached []string `json:"detached,omitempty"`
+}
+
+// builder builds the documentation model of the single document.
+type builder struct {
+	*Generator
+	combined bool
+	// messages and enums are the models referenced by the fields of the request and response messages.
+	messages map[string]*protogen.Message
+	enums    map[string]*protogen.Enum
+	// built are the messages already built, every message is built once per document.
+	built map[string]*Message
+}
+
+// BuildDocument returns the documentation model of the services. The service document has the single service,
+// the combined one shares the models among several services.
+func (g *Generator) BuildDocument(title string, services []*protogen.Service, combined bool) (*Document, error) {
+	b := &builder{
+		Generator: g,
+		combined:  combined,
+		messages:  make(map[string]*protogen.Message),
+		enums:     make(map[string]*protogen.Enum),
+		built:     make(map[string]*Message),
+	}
+
+	services = g.visibleServices(services)
+
+	serviceMethods := make([][]*protogen.Method, 0, len(services))
+	for _, service := range services {
+		methods, err := g.serviceMethods(service)
+		if err != nil {
+			return nil, err
+		}
+		for _, method := range methods {
+			b.collectModels(method.Input)
+			b.collectModels(method.Output)
+		}
+		serviceMethods = append(serviceMethods, methods)
+	}
+
+	d := &Document{Title: title, Combined: combined}
+	if g.opts.Errors != ErrorsOmit {
+		d.ErrorCodes = copyTwirpErrorCodes()
+	}
+
+	for _, k := range b.modelKeys() {
+		if message, ok := b.messages[k]; ok {
+			m, err := b.buildMessage(message)
+			if err != nil {
+				return nil, err
+			}
+			d.Messages = append(d.Messages, m)
+			continue
+		}
+		d.Enums = append(d.Enums, b.buildEnum(b.enums[k]))
+	}
+
+	for i, service := range services {
+		s, err := b.buildService(service, serviceMethods[i])
+		if err != nil {
+			if combined {
+				return nil, fmt.Errorf("service %s: %w", service.Desc.FullName(), err)
+			}
+			return nil, err
+		}
+		d.Services = append(d.Services, s)
+	}
+
+	b.assignAnchors(d)
+	return d, nil
+}
+
+// assignAnchors sets the anchors of the services, methods and models. The anchors are generated by md.Anchors
+// for the headers in the order the built-in layout prints them, so the repeated header gets the same suffix
+// as on GitHub and in the HTML site. The headers after the models do not affect their anchors.
+func (b *builder) assignAnchors(d *Document) {
+	anchors := make(md.Anchors)
+	models := d.models()
+
+	if d.Combined {
+		anchors.Anchor(d.Title)
+		anchors.Anchor("Services")
+	}
+	for _, s := range d.Services {
+		if !d.Combined {
+			s.Anchor = anchors.Anchor(s.Name)
+			anchors.Anchor("Methods")
+		}
+	}
+	if len(models) > 0 {
+		anchors.Anchor("Models")
+	}
+
+	for _, s := range d.Services {
+		if d.Combined {
+			s.Anchor = anchors.Anchor(s.FullName)
+		} else {
+			anchors.Anchor("Methods")
+		}
+
+		for _, m := range s.Methods {
+			m.Anchor = anchors.Anchor("POST " + m.HeadingPath)
+			anchors.Anchor("Request")
+			anchors.Anchor("Response")
+			if len(m.Errors) > 0 {
+				anchors.Anchor("Errors")
+			}
+			if len(m.Samples) > 0 {
+				anchors.Anchor("Samples")
+			}
+		}
+	}
+
+	if len(models) > 0 {
+		anchors.Anchor("Models")
+	}
+	modelAnchors := make(map[string]string, len(models))
+	for _, model := range models {
+		switch m := model.(type) {
+		case *Message:
+			m.Anchor = anchors.Anchor(m.FullName)
+			modelAnchors[m.FullName] = m.Anchor
+		case *Enum:
+			m.Anchor = anchors.Anchor(m.FullName)
+			modelAnchors[m.FullName] = m.Anchor
+		}
+	}
+
+	for _, m := range b.built {
+		for _, f := range m.Fields {
+			for _, t := range []*Type{f.Type, f.Type.MapKey, f.Type.MapValue} {
+				if t != nil && t.Model != "" {
+					t.Anchor = modelAnchors[t.Model]
+				}
+			}
+		}
+	}
+}
+
+// collectModels collects the messages and enums referenced by the visible fields of the message.
+func (b *builder) collectModels(message *protogen.Message) {
+	for _, field := range message.Fields {
+		if b.opts.Hidden(field.Desc) {
+			continue
+		}
+		switch field.Desc.Kind() { //nolint:exhaustive
+		case protoreflect.MessageKind, protoreflect.GroupKind:
+			name := string(field.Message.Desc.FullName())
+			if !field.Desc.IsMap() {
+				if _, ok := protoKnownTypeLabels[field.Message.Desc.FullName()]; ok {
+					break
+				}
+				if _, ok := b.messages[name]; ok {
+					break
+				}
+				b.messages[name] = field.Message
+			}
+			b.collectModels(field.Message)
+		case protoreflect.EnumKind:
+			if _, ok := protoKnownTypeLabels[field.Enum.Desc.FullName()]; ok {
+				break
+			}
+			b.enums[string(field.Enum.Desc.FullName())] = field.Enum
+		}
+	}
+}
+
+// modelKeys returns the full names of the collected models sorted.
+func (b *builder) modelKeys() []string {
+	keys := make([]string, 0, len(b.messages)+len(b.enums))
+	for k := range b.enums {
+		keys = append(keys, k)
+	}
+	for k := range b.messages {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (b *builder) buildService(service *protogen.Service, methods []*protogen.Method) (*Service, error) {
+	s := &Service{
+		Name:       string(service.Desc.Name()),
+		FullName:   string(service.Desc.FullName()),
+		Package:    string(service.Desc.ParentFile().Package()),
+		BaseURL:    b.opts.BaseURL + "/" + string(service.Desc.FullName()),
+		Comments:   newComments(service.Comments),
+		Deprecated: isDeprecated(service.Desc),
+	}
+
+	for _, method := range methods {
+		errs, err := methodErrors(method.Desc)
+		if err != nil {
+			return nil, err
+		}
+
+		m := &Method{
+			Name:        string(method.Desc.Name()),
+			Path:        fmt.Sprintf("/%s/%s", service.Desc.FullName(), method.Desc.Name()),
+			HeadingPath: methodHeaderPath(service, method, b.combined),
+			Streaming:   streamingKind(method),
+			Comments:    newComments(method.Comments),
+			Deprecated:  isDeprecated(method.Desc),
+			Errors:      errs,
+		}
+		if m.Request, err = b.buildMessage(method.Input); err != nil {
+			return nil, err
+		}
+		if m.Response, err = b.buildMessage(method.Output); err != nil {
+			return nil, err
+		}
+		m.URL = b.opts.BaseURL + m.Path
+		m.Samples = b.methodSamples(service, method, m)
+
+		s.Methods = append(s.Methods, m)
+	}
+
+	return s, nil
+}
+
+// buildMessage returns the model of the message, every message is built once per document.
+func (b *builder) buildMessage(message *protogen.Message) (*Message, error) {
+	name := string(message.Desc.FullName())
+	if m, ok := b.built[name]; ok {
+		return m, nil
+	}
+
+	m := &Message{
+		Name:       string(message.Desc.Name()),
+		FullName:   name,
+		Comments:   newComments(message.Comments),
+		Deprecated: isDeprecated(message.Desc),
+		Examples:   b.buildExamples(message.Desc),
+	}
+	b.built[name] = m
+
+	oneofs := make(map[*protogen.Oneof]bool)
+	for _, field := range message.Fields {
+		if b.opts.Hidden(field.Desc) {
+			continue
+		}
+
+		t, err := fieldType(field)
+		if err != nil {
+			return nil, err
+		}
+		f := &Field{
+			Name:       string(field.Desc.Name()),
+			JSONName:   field.Desc.JSONName(),
+			Type:       t,
+			Comments:   newComments(field.Comments),
+			Deprecated: isDeprecated(field.Desc),
+		}
+
+		if oneof := field.Oneof; oneof != nil && !oneof.Desc.IsSynthetic() {
+			f.Oneof = string(oneof.Desc.Name())
+			if !oneofs[oneof] {
+				oneofs[oneof] = true
+				m.Oneofs = append(m.Oneofs, &Oneof{Name: f.Oneof, Comments: newComments(oneof.Comments)})
+			}
+		}
+
+		m.Fields = append(m.Fields, f)
+	}
+
+	return m, nil
+}
+
+func (b *builder) buildEnum(enum *protogen.Enum) *Enum {
+	e := &Enum{
+		Name:       string(enum.Desc.Name()),
+		FullName:   string(enum.Desc.FullName()),
+		Comments:   newComments(enum.Comments),
+		Deprecated: isDeprecated(enum.Desc),
+	}
+
+	for _, ev := range enum.Values {
+		if b.opts.Hidden(ev.Desc) {
+			continue
+		}
+		e.Values = append(e.Values, &EnumValue{
+			Name:       string(ev.Desc.Name()),
+			Number:     int32(ev.Desc.Number()),
+			Comments:   newComments(ev.Comments),
+			Deprecated: isDeprecated(ev.Desc),
+		})
+	}
+
+	return e
+}
+
+// buildExamples returns the examples of the message. The examples declared by the (twirp_doc.examples) option
+// are used as is, otherwise one example is generated per oneof variant if the message has oneofs.
+func (g *Generator) buildExamples(mdesc protoreflect.MessageDescriptor) []*Example {
+	var examples []*Example
+
+	if declared := messageExamples(mdesc); len(declared) > 0 {
+		for _, example := range declared {
+			m, err := g.declaredExampleMessage(mdesc, example)
+			examples = append(examples, &Example{msg: m, Err: err})
+		}
+	} else {
+		variants := g.oneofVariants(mdesc)
+		for _, i := range variants {
+			e := new(Example)
+			if len(variants) > 1 {
+				for _, f := range oneofVariantFields(mdesc, i) {
+					e.Variant = append(e.Variant, f.JSONName())
+				}
+			}
+			e.msg, e.Err = g.sampleMessage(mdesc, i)
+			examples = append(examples, e)
+		}
+	}
+
+	for _, e := range examples {
+		for _, encoding := range g.opts.Encodings {
+			if e.Err != nil {
+				break
+			}
+			switch encoding {
+			case ContentJSON:
+				e.JSON, e.Err = messageJSONFormat(e.msg)
+			case ContentProtobuf:
+				e.Binary, e.Err = messageBinaryFormat(e.msg, g.opts.BinaryDump)
+			}
+		}
+	}
+
+	return examples
+}
+
+// fieldType returns the resolved type of the field.
+func fieldType(field *protogen.Field) (*Type, error) {
+	t := &Type{Kind: field.Desc.Kind().String(), Repeated: field.Desc.IsList()}
+
+	//nolint:exhaustive
+	switch field.Desc.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind, protoreflect.EnumKind:
+		if field.Desc.IsMap() {
+			t.Label = "map"
+			for _, f := range field.Message.Fields {
+				ft, err := fieldType(f)
+				if err != nil {
+					return nil, err
+				}
+				if f.Desc == field.Desc.MapKey() {
+					t.MapKey = ft
+				} else {
+					t.MapValue = ft
+				}
+			}
+			break
+		}
+
+		var name protoreflect.FullName
+		if field.Message != nil {
+			name = field.Message.Desc.FullName()
+		} else {
+			name = field.Enum.Desc.FullName()
+		}
+		t.FullName = string(name)
+
+		if s, ok := protoKnownTypeLabels[name]; ok {
+			t.Label = s
+			break
+		}
+
+		t.Label = string(name)
+		t.Model = string(name)
+
+	default:
+		s, ok := protoKindTypes[field.Desc.Kind()]
+		if !ok {
+			return nil, fmt.Errorf("field %s: unknown kind %s", field.Desc.FullName(), field.Desc.Kind())
+		}
+		t.Label = s
+	}
+
+	return t, nil
+}
+
+func newComments(cs protogen.CommentSet) *Comments {
+	c := &Comments{
+		Leading:  string(withoutDirectives(cs.Leading)),
+		Trailing: string(withoutDirectives(cs.Trailing)),
+	}
+	for _, d := range cs.LeadingDetached {
+		if d = withoutDirectives(d); d != "" {
+			c.Detached = append(c.Detached, string(d))
+		}
+	}
+	return c
+}
+
+// modelAnchor returns the anchor of the documented model referenced by the full name or the name suffix.
+func (d *Document) modelAnchor(ref string) (string, bool) {
+	models := d.models()
+	for _, model := range models {
+		if name, anchor := modelNameAnchor(model); name == ref {
+			return anchor, true
+		}
+	}
+	for _, model := range models {
+		if name, anchor := modelNameAnchor(model); strings.HasSuffix(name, "."+ref) {
+			return anchor, true
+		}
+	}
+	return "", false
+}
+
+func modelNameAnchor(model interface{}) (string, string) {
+	switch m := model.(type) {
+	case *Message:
+		return m.FullName, m.Anchor
+	case *Enum:
+		return m.FullName, m.Anchor
+	}
+	return "", ""
+}
+
+// models returns the messages and enums of the document sorted by full name.
+func (d *Document) models() []interface{} {
+	models := make([]interface{}, 0, len(d.Messages)+len(d.Enums))
+	names := make([]string, 0, cap(models))
+	for _, m := range d.Messages {
+		models = append(models, m)
+		names = append(names, m.FullName)
+	}
+	for _, e := range d.Enums {
+		models = append(models, e)
+		names = append(names, e.FullName)
+	}
+
+	sort.Sort(&byName{models: models, names: names})
+	return models
+}
+
+type byName struct {
+	models []interface{}
+	names  []string
+}
+
+func (s *byName) Len() int           { return len(s.models) }
+func (s *byName) Less(i, j int) bool { return s.names[i] < s.names[j] }
+func (s *byName) Swap(i, j int) {
+	s.models[i], s.models[j] = s.models[j], s.models[i]
+	s.names[i], s.names[j] = s.names[j], s.names[i]
+}
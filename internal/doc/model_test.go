@@ -0,0 +1,205 @@
+package doc_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/compiler/protogen"
+
+	"github.com/albenik/twirp-doc-gen/internal/doc"
+)
+
+func buildDocument(t *testing.T, fixture string, opts *doc.Options, services ...string) *doc.Document {
+	t.Helper()
+
+	plugin := loadPlugin(t, fixture)
+	found := make([]*protogen.Service, 0, len(services))
+	for _, name := range services {
+		found = append(found, findService(t, plugin, name))
+	}
+
+	d, err := doc.NewGenerator(nil, opts).BuildDocument("API", found, len(services) > 1)
+	require.NoError(t, err)
+	return d
+}
+
+func TestBuildDocument(t *testing.T) {
+	t.Parallel()
+
+	d := buildDocument(t, "models.textproto", testOptions(), "UserService")
+	require.Equal(t, "API", d.Title)
+	require.False(t, d.Combined)
+	require.Len(t, d.Services, 1)
+
+	s := d.Services[0]
+	require.Equal(t, "UserService", s.Name)
+	require.Equal(t, "test.v1.UserService", s.FullName)
+	require.Equal(t, "test.v1", s.Package)
+	require.Equal(t, "#userservice", s.Anchor)
+	require.Equal(t, testBaseURL+"/test.v1.UserService", s.BaseURL)
+	require.Len(t, s.Methods, 1)
+
+	m := s.Methods[0]
+	require.Equal(t, "Get_User", m.Name)
+	require.Equal(t, "/test.v1.UserService/Get_User", m.Path)
+	require.Equal(t, "/Get_User", m.HeadingPath)
+	require.Equal(t, testBaseURL+"/test.v1.UserService/Get_User", m.URL)
+	require.Equal(t, "#post-get_user", m.Anchor)
+	require.Equal(t, "test.v1.GetUserRequest", m.Request.FullName)
+	require.Empty(t, m.Request.Anchor)
+	require.Len(t, m.Request.Examples, 1)
+	require.JSONEq(t, `{"id": "foo"}`, m.Request.Examples[0].JSON)
+
+	user := m.Response
+	require.Equal(t, "User", user.Name)
+	require.Empty(t, user.Anchor)
+	require.Equal(t, []string{"id", "status", "roles", "profile", "labels"}, fieldNames(user))
+
+	require.Equal(t, &doc.Type{Label: "string", Kind: "string"}, user.Fields[0].Type)
+	require.Equal(t, &doc.Type{
+		Label:    "test.v1.User.Status",
+		Kind:     "enum",
+		FullName: "test.v1.User.Status",
+		Model:    "test.v1.User.Status",
+		Anchor:   "#testv1userstatus",
+	}, user.Fields[1].Type)
+	require.True(t, user.Fields[2].Type.Repeated)
+	require.Equal(t, "test.v1.Role", user.Fields[2].Type.Model)
+
+	labels := user.Fields[4].Type
+	require.Equal(t, "map", labels.Label)
+	require.Equal(t, "message", labels.Kind)
+	require.Empty(t, labels.FullName)
+	require.False(t, labels.Repeated)
+	require.Equal(t, &doc.Type{Label: "string", Kind: "string"}, labels.MapKey)
+	require.Equal(t, "#testv1user_profile", labels.MapValue.Anchor)
+
+	require.Equal(t, []string{"test.v1.User_Profile"}, messageNames(d.Messages))
+	require.Equal(t, d.Messages[0].FullName, labels.MapValue.Model)
+	require.Len(t, d.Enums, 2)
+	require.Equal(t, "test.v1.Role", d.Enums[0].FullName)
+	require.Equal(t, &doc.EnumValue{Name: "ROLE_ADMIN", Number: 1, Comments: &doc.Comments{}}, d.Enums[0].Values[1])
+}
+
+func TestBuildDocument_ErrorCodes(t *testing.T) {
+	t.Parallel()
+
+	d := buildDocument(t, "models.textproto", testOptions(), "UserService")
+	require.Len(t, d.ErrorCodes, 18)
+	require.Equal(t, "invalid_argument", d.ErrorCodes[0].Code)
+
+	d.ErrorCodes[0].HTTPStatus = 418
+	d.ErrorCodes[1] = &doc.ErrorCode{Code: "teapot"}
+
+	d = buildDocument(t, "models.textproto", testOptions(), "UserService")
+	require.Equal(t, "invalid_argument", d.ErrorCodes[0].Code)
+	require.Equal(t, 400, d.ErrorCodes[0].HTTPStatus)
+	require.Equal(t, "malformed", d.ErrorCodes[1].Code)
+}
+
+func TestBuildDocument_Combined(t *testing.T) {
+	t.Parallel()
+
+	d := buildDocument(t, "multi.textproto", testOptions(), "UserService", "GroupService")
+	require.True(t, d.Combined)
+	require.Len(t, d.Services, 2)
+	require.Equal(t, "#testv1userservice", d.Services[0].Anchor)
+	require.Equal(t, "/test.v1.UserService/Get", d.Services[0].Methods[0].HeadingPath)
+	require.Equal(t, "#post-testv1userserviceget", d.Services[0].Methods[0].Anchor)
+	require.Same(t, d.Services[0].Methods[0].Request, d.Services[1].Methods[1].Request)
+	require.Same(t, d.Messages[1], d.Services[0].Methods[0].Response)
+	require.Equal(t, "#testv1user", d.Messages[1].Anchor)
+	require.Equal(t, []string{"test.v1.Ref", "test.v1.User"}, messageNames(d.Messages))
+}
+
+func TestBuildDocument_Oneof(t *testing.T) {
+	t.Parallel()
+
+	d := buildDocument(t, "oneof.textproto", testOptions(), "SearchService")
+	req := d.Services[0].Methods[0].Request
+
+	require.Len(t, req.Oneofs, 1)
+	require.Equal(t, "owner", req.Oneofs[0].Name)
+	require.Equal(t, "owner", req.Fields[1].Oneof)
+	require.Equal(t, "owner", req.Fields[2].Oneof)
+	require.Empty(t, req.Fields[3].Oneof)
+
+	require.Len(t, req.Examples, 2)
+	require.Equal(t, []string{"userId"}, req.Examples[0].Variant)
+	require.Equal(t, []string{"groupId"}, req.Examples[1].Variant)
+	require.NoError(t, req.Examples[1].Err)
+}
+
+func TestBuildDocument_Errors(t *testing.T) {
+	t.Parallel()
+
+	d := buildDocument(t, "errors.textproto", testOptions(), "UserService")
+	m := d.Services[0].Methods[0]
+
+	require.Equal(t, &doc.Comments{Leading: " Returns the user.\n\n"}, m.Comments)
+	require.Equal(t, []*doc.ErrorSpec{
+		{
+			Code:        "invalid_argument",
+			HTTPStatus:  400,
+			Description: "the user id is empty",
+			Meta:        []*doc.ErrorMeta{{Key: "argument", Description: "the name of the invalid field"}},
+		},
+		{
+			Code:        "not_found",
+			HTTPStatus:  404,
+			Description: "the user does not exist",
+			Meta:        []*doc.ErrorMeta{{Key: "user_id", Description: "the id of the missing user"}},
+		},
+		{Code: "permission_denied", HTTPStatus: 403},
+	}, m.Errors)
+
+	_, err := doc.NewGenerator(nil, testOptions()).
+		BuildDocument("API", []*protogen.Service{findService(t, loadPlugin(t, "errors.textproto"), "BadService")}, false)
+	require.Error(t, err)
+}
+
+func TestBuildDocument_Hidden(t *testing.T) {
+	t.Parallel()
+
+	d := buildDocument(t, "internal.textproto", testOptions(), "AccountService", "AdminService")
+
+	require.Len(t, d.Services, 1)
+	require.Len(t, d.Services[0].Methods, 1)
+
+	account := d.Services[0].Methods[0].Request
+	require.Equal(t, []string{"id", "role"}, fieldNames(account))
+	require.Len(t, d.Enums, 1)
+	require.Len(t, d.Enums[0].Values, 1)
+	require.Equal(t, "ROLE_USER", d.Enums[0].Values[0].Name)
+}
+
+func TestBuildDocument_Samples(t *testing.T) {
+	t.Parallel()
+
+	opts := testOptions()
+	opts.Samples = []string{doc.SampleCurl, doc.SamplePython}
+	d := buildDocument(t, "models.textproto", opts, "UserService")
+
+	samples := d.Services[0].Methods[0].Samples
+	require.Len(t, samples, 2)
+	require.Equal(t, doc.SampleCurl, samples[0].Language)
+	require.Equal(t, "sh", samples[0].Syntax)
+	require.Contains(t, samples[0].Code, testBaseURL+"/test.v1.UserService/Get_User")
+	require.Equal(t, "Python", samples[1].Label)
+}
+
+func fieldNames(m *doc.Message) []string {
+	names := make([]string, 0, len(m.Fields))
+	for _, f := range m.Fields {
+		names = append(names, f.JSONName)
+	}
+	return names
+}
+
+func messageNames(messages []*doc.Message) []string {
+	names := make([]string, 0, len(messages))
+	for _, m := range messages {
+		names = append(names, m.FullName)
+	}
+	return names
+}
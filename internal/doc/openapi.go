@@ -0,0 +1,352 @@
+package doc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	openAPIVersion        = "3.1.0"
+	openAPIDefaultVersion = "1.0.0"
+	openAPISchemaRef      = "#/components/schemas/"
+	openAPITwirpError     = "twirp.Error"
+)
+
+// OpenAPI encodings.
+const (
+	EncodingJSON = "json"
+	EncodingYAML = "yaml"
+)
+
+type openAPIDocument struct {
+	OpenAPI    string                             `json:"openapi" yaml:"openapi"`
+	Info       *openAPIInfo                       `json:"info" yaml:"info"`
+	Servers    []*openAPIServer                   `json:"servers,omitempty" yaml:"servers,omitempty"`
+	Paths      map[string]map[string]*openAPIPath `json:"paths" yaml:"paths"`
+	Components *openAPIComponents                 `json:"components,omitempty" yaml:"components,omitempty"`
+}
+
+type openAPIInfo struct {
+	Title       string `json:"title" yaml:"title"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Version     string `json:"version" yaml:"version"`
+}
+
+type openAPIServer struct {
+	URL string `json:"url" yaml:"url"`
+}
+
+type openAPIPath struct {
+	OperationID string                      `json:"operationId" yaml:"operationId"`
+	Summary     string                      `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Description string                      `json:"description,omitempty" yaml:"description,omitempty"`
+	Deprecated  bool                        `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	RequestBody *openAPIRequestBody         `json:"requestBody" yaml:"requestBody"`
+	Responses   map[string]*openAPIResponse `json:"responses" yaml:"responses"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                         `json:"required" yaml:"required"`
+	Content  map[string]*openAPIMediaType `json:"content" yaml:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                       `json:"description" yaml:"description"`
+	Content     map[string]*openAPIMediaType `json:"content,omitempty" yaml:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema *openAPISchema `json:"schema" yaml:"schema"`
+}
+
+type openAPIComponents struct {
+	Schemas map[string]*openAPISchema `json:"schemas" yaml:"schemas"`
+}
+
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Type                 interface{}               `json:"type,omitempty" yaml:"type,omitempty"`
+	Format               string                    `json:"format,omitempty" yaml:"format,omitempty"`
+	Pattern              string                    `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	Description          string                    `json:"description,omitempty" yaml:"description,omitempty"`
+	Enum                 []string                  `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Items                *openAPISchema            `json:"items,omitempty" yaml:"items,omitempty"`
+	Properties           map[string]*openAPISchema `json:"properties,omitempty" yaml:"properties,omitempty"`
+	Required             []string                  `json:"required,omitempty" yaml:"required,omitempty"`
+	AdditionalProperties interface{}               `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+	OneOf                []*openAPISchema          `json:"oneOf,omitempty" yaml:"oneOf,omitempty"`
+	AllOf                []*openAPISchema          `json:"allOf,omitempty" yaml:"allOf,omitempty"`
+	Deprecated           bool                      `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+}
+
+// GenerateServiceOpenAPI writes the OpenAPI 3.1 document of the service in the given encoding.
+func (g *Generator) GenerateServiceOpenAPI(service *protogen.Service, encoding string) error {
+	d, err := g.BuildDocument(string(service.Desc.Name()), []*protogen.Service{service}, false)
+	if err != nil {
+		return err
+	}
+	if len(d.Services) == 0 {
+		return fmt.Errorf("service %s is hidden", service.Desc.FullName())
+	}
+	return g.generateOpenAPI(d, commentsText(d.Services[0].Comments), encoding)
+}
+
+// GenerateOpenAPI writes the combined OpenAPI 3.1 document of several services in the given encoding.
+func (g *Generator) GenerateOpenAPI(title string, services []*protogen.Service, encoding string) error {
+	d, err := g.BuildDocument(title, services, true)
+	if err != nil {
+		return err
+	}
+	return g.generateOpenAPI(d, "", encoding)
+}
+
+func (g *Generator) generateOpenAPI(d *Document, description, encoding string) error {
+	doc := &openAPIDocument{
+		OpenAPI: openAPIVersion,
+		Info: &openAPIInfo{
+			Title:       d.Title,
+			Description: description,
+			Version:     openAPIDefaultVersion,
+		},
+		Servers: []*openAPIServer{{URL: g.opts.BaseURL}},
+		Paths:   make(map[string]map[string]*openAPIPath),
+		Components: &openAPIComponents{
+			Schemas: map[string]*openAPISchema{openAPITwirpError: twirpErrorSchema()},
+		},
+	}
+
+	messages := make([]*Message, 0, len(d.Messages))
+	for _, s := range d.Services {
+		for _, m := range s.Methods {
+			messages = append(messages, m.Request, m.Response)
+			doc.Paths[m.Path] = map[string]*openAPIPath{"post": g.openAPIOperation(s, m, d.Combined)}
+		}
+	}
+	messages = append(messages, d.Messages...)
+
+	for _, m := range messages {
+		schema, err := messageSchema(m)
+		if err != nil {
+			return err
+		}
+		doc.Components.Schemas[m.FullName] = schema
+	}
+	for _, e := range d.Enums {
+		doc.Components.Schemas[e.FullName] = enumSchema(e)
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+	switch encoding {
+	case EncodingJSON:
+		data, err = json.MarshalIndent(doc, "", "  ")
+	case EncodingYAML:
+		data, err = yaml.Marshal(doc)
+	default:
+		return fmt.Errorf("unknown OpenAPI encoding %q", encoding)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = g.writer.Write(data)
+	return err
+}
+
+// openAPIOperation returns the operation of the method. The combined document prefixes the operation ID
+// with the service name to keep it unique.
+func (g *Generator) openAPIOperation(s *Service, m *Method, combined bool) *openAPIPath {
+	desc := commentsText(m.Comments)
+	if m.Streaming != "" {
+		desc = strings.TrimSpace(fmt.Sprintf("%s\n\nWarning: the method is declared as %s RPC, "+
+			"Twirp does not support streaming.", desc, m.Streaming))
+	}
+
+	op := &openAPIPath{
+		OperationID: m.Name,
+		Summary:     strings.SplitN(desc, "\n", 2)[0], //nolint:gomnd
+		Description: desc,
+		Deprecated:  m.Deprecated || s.Deprecated,
+		RequestBody: &openAPIRequestBody{
+			Required: true,
+			Content:  g.messageContent(refSchema(m.Request.FullName)),
+		},
+		Responses: map[string]*openAPIResponse{
+			strconv.Itoa(http.StatusOK): {
+				Description: "OK",
+				Content:     g.messageContent(refSchema(m.Response.FullName)),
+			},
+		},
+	}
+
+	if combined {
+		op.OperationID = s.Name + "_" + op.OperationID
+	}
+
+	for _, c := range twirpErrorCodes {
+		status := strconv.Itoa(c.HTTPStatus)
+		if resp, ok := op.Responses[status]; ok {
+			resp.Description += ", " + c.Code
+			continue
+		}
+		op.Responses[status] = &openAPIResponse{
+			Description: "Twirp error: " + c.Code,
+			Content:     jsonContent(refSchema(openAPITwirpError)),
+		}
+	}
+
+	return op
+}
+
+func messageSchema(m *Message) (*openAPISchema, error) {
+	schema := &openAPISchema{
+		Type:        "object",
+		Description: commentsText(m.Comments),
+		Properties:  make(map[string]*openAPISchema, len(m.Fields)),
+		Deprecated:  m.Deprecated,
+	}
+
+	for _, f := range m.Fields {
+		fs, err := fieldSchema(f.Type)
+		if err != nil {
+			return nil, fmt.Errorf("field %s.%s: %w", m.FullName, f.Name, err)
+		}
+		s := *fs
+		s.Description = commentsText(f.Comments)
+		s.Deprecated = f.Deprecated
+		schema.Properties[f.JSONName] = &s
+	}
+
+	oneofs := make([]*openAPISchema, 0, len(m.Oneofs))
+	for _, oneof := range m.Oneofs {
+		alternatives := make([]*openAPISchema, 0, len(m.Fields))
+		for _, f := range m.Fields {
+			if f.Oneof == oneof.Name {
+				alternatives = append(alternatives, &openAPISchema{Required: []string{f.JSONName}})
+			}
+		}
+		oneofs = append(oneofs, &openAPISchema{OneOf: alternatives})
+	}
+
+	switch len(oneofs) {
+	case 0:
+	case 1:
+		schema.OneOf = oneofs[0].OneOf
+	default:
+		schema.AllOf = oneofs
+	}
+
+	return schema, nil
+}
+
+func enumSchema(e *Enum) *openAPISchema {
+	schema := &openAPISchema{
+		Type:        "string",
+		Description: commentsText(e.Comments),
+		Enum:        make([]string, 0, len(e.Values)),
+		Deprecated:  e.Deprecated,
+	}
+	for _, v := range e.Values {
+		schema.Enum = append(schema.Enum, v.Name)
+	}
+	return schema
+}
+
+// fieldSchema returns the schema of the field type, the documented models are referenced.
+func fieldSchema(t *Type) (*openAPISchema, error) {
+	var schema *openAPISchema
+
+	switch {
+	case t.MapKey != nil:
+		valSchema, err := fieldSchema(t.MapValue)
+		if err != nil {
+			return nil, err
+		}
+		return &openAPISchema{Type: "object", AdditionalProperties: valSchema}, nil
+
+	case t.FullName != "":
+		if s, ok := protoKnownTypeSchemas[protoreflect.FullName(t.FullName)]; ok {
+			schema = s
+		} else {
+			schema = refSchema(t.FullName)
+		}
+
+	default:
+		for kind, s := range protoKindSchemas {
+			if kind.String() == t.Kind {
+				schema = s
+			}
+		}
+	}
+
+	if schema == nil {
+		return nil, fmt.Errorf("unknown kind %s", t.Kind)
+	}
+
+	if t.Repeated {
+		return &openAPISchema{Type: "array", Items: schema}, nil
+	}
+	return schema, nil
+}
+
+func twirpErrorSchema() *openAPISchema {
+	codes := make([]string, 0, len(twirpErrorCodes))
+	for _, c := range twirpErrorCodes {
+		codes = append(codes, c.Code)
+	}
+
+	return &openAPISchema{
+		Type:        "object",
+		Description: "Twirp error " + twirpErrorCodesURL,
+		Properties: map[string]*openAPISchema{
+			"code": {Type: "string", Enum: codes},
+			"msg":  {Type: "string"},
+			"meta": {Type: "object", AdditionalProperties: &openAPISchema{Type: "string"}},
+		},
+		Required: []string{"code", "msg"},
+	}
+}
+
+func refSchema(name string) *openAPISchema {
+	return &openAPISchema{Ref: openAPISchemaRef + name}
+}
+
+func jsonContent(schema *openAPISchema) map[string]*openAPIMediaType {
+	return map[string]*openAPIMediaType{contentTypeJSON: {Schema: schema}}
+}
+
+// messageContent returns the content of the request or response message in every documented encoding.
+func (g *Generator) messageContent(schema *openAPISchema) map[string]*openAPIMediaType {
+	content := make(map[string]*openAPIMediaType, len(g.opts.Encodings))
+	for _, encoding := range g.opts.Encodings {
+		switch encoding {
+		case ContentJSON:
+			content[contentTypeJSON] = &openAPIMediaType{Schema: schema}
+		case ContentProtobuf:
+			content[contentTypeProtobuf] = &openAPIMediaType{Schema: &openAPISchema{
+				Type:        "string",
+				Format:      "binary",
+				Description: "Protobuf binary encoding of " + strings.TrimPrefix(schema.Ref, openAPISchemaRef),
+			}}
+		}
+	}
+	return content
+}
+
+// commentText returns the comment as a plain text without leading spaces of the lines.
+func commentText(c protogen.Comments) string {
+	lines := strings.Split(strings.TrimSpace(string(withoutDirectives(c))), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimSpace(l)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,163 @@
+package doc_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/albenik/twirp-doc-gen/internal/doc"
+)
+
+func TestGenerator_GenerateServiceOpenAPI(t *testing.T) {
+	t.Parallel()
+
+	service := findService(t, loadPlugin(t, "oneof.textproto"), "SearchService")
+
+	jsonBuf := bytes.NewBuffer(nil)
+	require.NoError(t, doc.NewGenerator(jsonBuf, testOptions()).GenerateServiceOpenAPI(service, doc.EncodingJSON))
+
+	var spec map[string]interface{}
+	require.NoError(t, json.Unmarshal(jsonBuf.Bytes(), &spec))
+
+	require.Equal(t, "3.1.0", spec["openapi"])
+	require.Equal(t, []interface{}{map[string]interface{}{"url": testBaseURL}}, spec["servers"])
+
+	op := spec["paths"].(map[string]interface{})["/test.v1.SearchService/Search"].(map[string]interface{})["post"].(map[string]interface{})
+	require.Equal(t, "Search", op["operationId"])
+	require.Equal(t, "#/components/schemas/test.v1.SearchRequest",
+		op["requestBody"].(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"].(map[string]interface{})["$ref"])
+
+	responses := op["responses"].(map[string]interface{})
+	require.Contains(t, responses, "200")
+	require.Equal(t, "Twirp error: invalid_argument, malformed, out_of_range", responses["400"].(map[string]interface{})["description"])
+	require.Equal(t, "Twirp error: unavailable", responses["503"].(map[string]interface{})["description"])
+	require.Equal(t, "Twirp error: unknown, internal, dataloss", responses["500"].(map[string]interface{})["description"])
+
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	require.Contains(t, schemas, "twirp.Error")
+	require.Contains(t, schemas, "test.v1.SearchResponse")
+
+	request := schemas["test.v1.SearchRequest"].(map[string]interface{})
+	require.Equal(t, map[string]interface{}{"type": "integer", "format": "int32"},
+		request["properties"].(map[string]interface{})["groupId"])
+	require.Equal(t, []interface{}{
+		map[string]interface{}{"required": []interface{}{"userId"}},
+		map[string]interface{}{"required": []interface{}{"groupId"}},
+	}, request["oneOf"])
+
+	yamlBuf := bytes.NewBuffer(nil)
+	require.NoError(t, doc.NewGenerator(yamlBuf, testOptions()).GenerateServiceOpenAPI(service, doc.EncodingYAML))
+
+	var yamlSpec map[string]interface{}
+	require.NoError(t, yaml.Unmarshal(yamlBuf.Bytes(), &yamlSpec))
+	require.Equal(t, "3.1.0", yamlSpec["openapi"])
+}
+
+func TestGenerator_GenerateServiceOpenAPI_Deprecated(t *testing.T) {
+	t.Parallel()
+
+	service := findService(t, loadPlugin(t, "deprecated.textproto"), "ItemService")
+
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, doc.NewGenerator(buf, testOptions()).GenerateServiceOpenAPI(service, doc.EncodingJSON))
+
+	var spec map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &spec))
+
+	paths := spec["paths"].(map[string]interface{})
+	require.Equal(t, true, paths["/test.v1.ItemService/OldGet"].(map[string]interface{})["post"].(map[string]interface{})["deprecated"])
+	require.NotContains(t, paths["/test.v1.ItemService/Get"].(map[string]interface{})["post"], "deprecated")
+
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	require.Equal(t, true, schemas["test.v1.LegacyInfo"].(map[string]interface{})["deprecated"])
+	item := schemas["test.v1.Item"].(map[string]interface{})["properties"].(map[string]interface{})
+	require.Equal(t, true, item["oldName"].(map[string]interface{})["deprecated"])
+
+	opts := testOptions()
+	opts.Deprecated = doc.DeprecatedHide
+
+	buf.Reset()
+	require.NoError(t, doc.NewGenerator(buf, opts).GenerateServiceOpenAPI(service, doc.EncodingJSON))
+	require.NotContains(t, buf.String(), "OldGet")
+	require.NotContains(t, buf.String(), "oldName")
+	require.NotContains(t, buf.String(), "STATUS_LEGACY")
+	require.NotContains(t, buf.String(), "LegacyInfo")
+}
+
+func TestGenerator_GenerateServiceOpenAPI_FixedKinds(t *testing.T) {
+	t.Parallel()
+
+	service := findService(t, loadPlugin(t, "fixed.textproto"), "CounterService")
+
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, doc.NewGenerator(buf, testOptions()).GenerateServiceOpenAPI(service, doc.EncodingJSON))
+
+	var spec map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &spec))
+
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	require.Equal(t, map[string]interface{}{
+		"a": map[string]interface{}{"type": "integer", "format": "uint32"},
+		"b": map[string]interface{}{"type": "string", "format": "uint64"},
+		"c": map[string]interface{}{"type": "integer", "format": "int32"},
+		"d": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string", "format": "int64"}},
+	}, schemas["test.v1.Counters"].(map[string]interface{})["properties"])
+}
+
+func TestGenerator_GenerateServiceOpenAPI_WellKnownTypes(t *testing.T) {
+	t.Parallel()
+
+	service := findService(t, loadPlugin(t, "wkt.textproto"), "RecordService")
+
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, doc.NewGenerator(buf, testOptions()).GenerateServiceOpenAPI(service, doc.EncodingJSON))
+
+	var spec map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &spec))
+
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	record := schemas["test.v1.Record"].(map[string]interface{})["properties"].(map[string]interface{})
+	require.Equal(t, map[string]interface{}{"type": []interface{}{"string", "null"}, "format": "date-time"}, record["createdAt"])
+	require.Equal(t, map[string]interface{}{"type": []interface{}{"string", "null"}, "pattern": `^-?\d+(\.\d+)?s$`}, record["ttl"])
+}
+
+func TestGenerator_GenerateServiceOpenAPI_Encodings(t *testing.T) {
+	t.Parallel()
+
+	opts := testOptions()
+	opts.Encodings = []string{doc.ContentJSON, doc.ContentProtobuf}
+	service := findService(t, loadPlugin(t, "oneof.textproto"), "SearchService")
+
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, doc.NewGenerator(buf, opts).GenerateServiceOpenAPI(service, doc.EncodingJSON))
+
+	var spec map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &spec))
+
+	op := spec["paths"].(map[string]interface{})["/test.v1.SearchService/Search"].(map[string]interface{})["post"].(map[string]interface{})
+	request := op["requestBody"].(map[string]interface{})["content"].(map[string]interface{})
+	require.Contains(t, request, "application/json")
+	require.Equal(t, map[string]interface{}{
+		"type":        "string",
+		"format":      "binary",
+		"description": "Protobuf binary encoding of test.v1.SearchRequest",
+	}, request["application/protobuf"].(map[string]interface{})["schema"])
+
+	responses := op["responses"].(map[string]interface{})
+	response := responses["200"].(map[string]interface{})["content"].(map[string]interface{})
+	require.Contains(t, response, "application/json")
+	require.Contains(t, response, "application/protobuf")
+	require.NotContains(t, responses["400"].(map[string]interface{})["content"], "application/protobuf")
+
+	opts.Encodings = []string{doc.ContentProtobuf}
+
+	buf.Reset()
+	require.NoError(t, doc.NewGenerator(buf, opts).GenerateServiceOpenAPI(service, doc.EncodingJSON))
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &spec))
+
+	op = spec["paths"].(map[string]interface{})["/test.v1.SearchService/Search"].(map[string]interface{})["post"].(map[string]interface{})
+	require.NotContains(t, op["requestBody"].(map[string]interface{})["content"], "application/json")
+}
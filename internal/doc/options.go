@@ -0,0 +1,214 @@
+package doc
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Streaming methods handling modes.
+const (
+	// StreamingWarn documents the streaming methods with a warning.
+	StreamingWarn = "warn"
+	// StreamingSkip omits the streaming methods.
+	StreamingSkip = "skip"
+	// StreamingError fails the generation.
+	StreamingError = "error"
+)
+
+// Invalid examples handling modes.
+const (
+	// InvalidExamplesError fails the generation.
+	InvalidExamplesError = "error"
+	// InvalidExamplesWarn documents a warning instead of the example.
+	InvalidExamplesWarn = "warn"
+)
+
+// Comments rendering modes.
+const (
+	// CommentsItalic renders the comment paragraphs in italics.
+	CommentsItalic = "italic"
+	// CommentsPlain renders the comment paragraphs as a plain text.
+	CommentsPlain = "plain"
+	// CommentsMarkdown renders the comments as CommonMark.
+	CommentsMarkdown = "markdown"
+)
+
+// Deprecated elements handling modes.
+const (
+	// DeprecatedMark documents the deprecated elements with the deprecation markers.
+	DeprecatedMark = "mark"
+	// DeprecatedHide omits the deprecated elements.
+	DeprecatedHide = "hide"
+)
+
+// Message encodings documented for the methods.
+const (
+	// ContentJSON is the application/json encoding.
+	ContentJSON = "json"
+	// ContentProtobuf is the application/protobuf encoding.
+	ContentProtobuf = "protobuf"
+)
+
+// Binary encoding dump formats.
+const (
+	// BinaryDumpHex dumps the binary encoding as hex bytes.
+	BinaryDumpHex = "hex"
+	// BinaryDumpBase64 dumps the binary encoding as base64 string.
+	BinaryDumpBase64 = "base64"
+)
+
+// Method call samples languages.
+const (
+	// SampleCurl is the curl command sample.
+	SampleCurl = "curl"
+	// SampleGo is the Go sample using the Twirp generated JSON client.
+	SampleGo = "go"
+	// SampleTypeScript is the TypeScript sample using fetch.
+	SampleTypeScript = "ts"
+	// SamplePython is the Python sample using requests.
+	SamplePython = "python"
+)
+
+// Global Twirp errors table modes.
+const (
+	// ErrorsFull prints the table of all Twirp error codes.
+	ErrorsFull = "full"
+	// ErrorsCollapse prints the table in the collapsed section.
+	ErrorsCollapse = "collapse"
+	// ErrorsOmit omits the table.
+	ErrorsOmit = "omit"
+	// ErrorsLink links the shared document instead of the table.
+	ErrorsLink = "link"
+)
+
+// Options configures the generator.
+type Options struct {
+	// BaseURL is the base URL of the Twirp server.
+	BaseURL string
+	// Streaming is the streaming methods handling mode.
+	Streaming string
+	// InvalidExamples is the invalid examples handling mode.
+	InvalidExamples string
+	// RawComments disables escaping of the proto comments for the trusted Markdown comments.
+	RawComments bool
+	// Comments is the comments rendering mode.
+	Comments string
+	// DetachedComments renders the leading detached comments as notes above the elements.
+	DetachedComments bool
+	// Deprecated is the deprecated elements handling mode.
+	Deprecated string
+	// Encodings are the message encodings documented for every method.
+	Encodings []string
+	// BinaryDump is the dump format of the binary encoding examples.
+	BinaryDump string
+	// Samples are the languages of the method call samples.
+	Samples []string
+	// Errors is the global Twirp errors table mode.
+	Errors string
+	// ErrorsURL is the URL of the shared Twirp errors document linked in the ErrorsLink mode.
+	ErrorsURL string
+	// Headers are the extra request headers of the samples, e.g. "Authorization: Bearer $TOKEN".
+	Headers []string
+	// Template is the text/template source replacing the built-in layout of the Markdown documents.
+	Template string
+}
+
+// Validate checks the options values.
+func (o *Options) Validate() error {
+	switch o.Streaming {
+	case StreamingWarn, StreamingSkip, StreamingError:
+	default:
+		return fmt.Errorf("unknown streaming mode %q", o.Streaming)
+	}
+
+	switch o.InvalidExamples {
+	case InvalidExamplesError, InvalidExamplesWarn:
+	default:
+		return fmt.Errorf("unknown invalid examples mode %q", o.InvalidExamples)
+	}
+
+	switch o.Comments {
+	case CommentsItalic, CommentsPlain, CommentsMarkdown:
+	default:
+		return fmt.Errorf("unknown comments mode %q", o.Comments)
+	}
+
+	switch o.Deprecated {
+	case DeprecatedMark, DeprecatedHide:
+	default:
+		return fmt.Errorf("unknown deprecated mode %q", o.Deprecated)
+	}
+
+	if len(o.Encodings) == 0 {
+		return fmt.Errorf("no encodings")
+	}
+	for _, e := range o.Encodings {
+		switch e {
+		case ContentJSON, ContentProtobuf:
+		default:
+			return fmt.Errorf("unknown encoding %q", e)
+		}
+	}
+
+	for _, sample := range o.Samples {
+		switch sample {
+		case SampleCurl, SampleGo, SampleTypeScript, SamplePython:
+		default:
+			return fmt.Errorf("unknown sample language %q", sample)
+		}
+	}
+
+	for _, h := range o.Headers {
+		if !strings.Contains(h, ":") {
+			return fmt.Errorf("invalid header %q", h)
+		}
+	}
+
+	switch o.Errors {
+	case ErrorsFull, ErrorsCollapse, ErrorsOmit:
+	case ErrorsLink:
+		if o.ErrorsURL == "" {
+			return fmt.Errorf("errors mode %q requires the errors URL", o.Errors)
+		}
+	default:
+		return fmt.Errorf("unknown errors mode %q", o.Errors)
+	}
+
+	switch o.BinaryDump {
+	case BinaryDumpHex, BinaryDumpBase64:
+	default:
+		return fmt.Errorf("unknown binary dump format %q", o.BinaryDump)
+	}
+
+	return nil
+}
+
+// Hidden reports whether the service, method, field or enum value is omitted from the documentation.
+// The internal elements are always hidden, the deprecated ones are hidden according to the options.
+// The fields of the deprecated message and enum types are hidden with their models.
+func (o *Options) Hidden(desc protoreflect.Descriptor) bool {
+	if isInternal(desc) {
+		return true
+	}
+	if o.Deprecated != DeprecatedHide {
+		return false
+	}
+	if isDeprecated(desc) {
+		return true
+	}
+
+	if fd, ok := desc.(protoreflect.FieldDescriptor); ok {
+		if fd.IsMap() {
+			fd = fd.MapValue()
+		}
+		if m := fd.Message(); m != nil && isDeprecated(m) {
+			return true
+		}
+		if e := fd.Enum(); e != nil && isDeprecated(e) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,246 @@
+package doc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const (
+	twirpPathPrefix     = "/twirp"
+	twirpImportPath     = "github.com/twitchtv/twirp"
+	protojsonImportPath = "google.golang.org/protobuf/encoding/protojson"
+)
+
+// sampleRequest is the method call shown by the samples.
+type sampleRequest struct {
+	baseURL string
+	url     string
+	service *protogen.Service
+	method  *protogen.Method
+	headers []string
+	body    string
+}
+
+type sampleLanguage struct {
+	label  string
+	syntax string
+	render func(r *sampleRequest) string
+}
+
+var sampleLanguages = map[string]*sampleLanguage{
+	SampleCurl:       {label: "curl", syntax: "sh", render: curlSample},
+	SampleGo:         {label: "Go", syntax: "go", render: goSample},
+	SampleTypeScript: {label: "TypeScript", syntax: "ts", render: typeScriptSample},
+	SamplePython:     {label: "Python", syntax: "python", render: pythonSample},
+}
+
+// methodSamples returns the method call samples with the first request example as the body. There are no samples
+// if the example can not be generated, the failure is reported by the request example.
+func (g *Generator) methodSamples(service *protogen.Service, method *protogen.Method, m *Method) []*Sample {
+	if len(g.opts.Samples) == 0 {
+		return nil
+	}
+
+	e := m.Request.Examples[0]
+	if e.Err != nil {
+		return nil
+	}
+	body, err := messageJSONCompact(e.msg)
+	if err != nil {
+		return nil
+	}
+
+	r := &sampleRequest{
+		baseURL: g.opts.BaseURL,
+		url:     m.URL,
+		service: service,
+		method:  method,
+		headers: g.opts.Headers,
+		body:    body,
+	}
+
+	samples := make([]*Sample, 0, len(g.opts.Samples))
+	for _, s := range g.opts.Samples {
+		lang := sampleLanguages[s]
+		samples = append(samples, &Sample{Language: s, Label: lang.label, Syntax: lang.syntax, Code: lang.render(r)})
+	}
+	return samples
+}
+
+// curlSample returns the curl command posting the JSON body. The headers are double quoted
+// to let the shell expand the variables like $TOKEN.
+func curlSample(r *sampleRequest) string {
+	lines := make([]string, 0, len(r.headers)+3) //nolint:gomnd
+	lines = append(lines,
+		"curl -X POST "+shellSingleQuote(r.url),
+		"  -H "+shellSingleQuote("Content-Type: "+contentTypeJSON),
+	)
+	for _, h := range r.headers {
+		lines = append(lines, "  -H "+shellDoubleQuote(h))
+	}
+	lines = append(lines, "  -d "+shellSingleQuote(r.body))
+
+	return strings.Join(lines, " \\\n")
+}
+
+// goSample returns the program calling the method with the Twirp generated JSON client. The base URL without
+// the default /twirp prefix is used as the server address, otherwise the client is created without the path prefix.
+func goSample(r *sampleRequest) string {
+	serviceImport, servicePkg := goPackage(r.service.Desc.ParentFile())
+	messageImport, messagePkg := goPackage(r.method.Input.Desc.ParentFile())
+
+	addr, prefixed := strings.TrimSuffix(r.baseURL, twirpPathPrefix), strings.HasSuffix(r.baseURL, twirpPathPrefix)
+	withTwirp := !prefixed || len(r.headers) > 0
+
+	imports := map[string]string{serviceImport: servicePkg, messageImport: messagePkg}
+	paths := make([]string, 0, len(imports))
+	for p := range imports {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	b := new(strings.Builder)
+	b.WriteString("package main\n\nimport (\n\t\"context\"\n\t\"log\"\n\t\"net/http\"\n\n")
+	if withTwirp {
+		fmt.Fprintf(b, "\t%q\n", twirpImportPath)
+	}
+	fmt.Fprintf(b, "\t%q\n\n", protojsonImportPath)
+	for _, p := range paths {
+		fmt.Fprintf(b, "\t%s %q\n", imports[p], p)
+	}
+	b.WriteString(")\n\nfunc main() {\n\tctx := context.Background()\n")
+
+	if prefixed {
+		fmt.Fprintf(b, "\tclient := %s.New%sJSONClient(%q, http.DefaultClient)\n", servicePkg, r.service.GoName, addr)
+	} else {
+		fmt.Fprintf(b, "\tclient := %s.New%sJSONClient(%q, http.DefaultClient, twirp.WithClientPathPrefix(\"\"))\n",
+			servicePkg, r.service.GoName, r.baseURL)
+	}
+	if len(r.headers) > 0 {
+		b.WriteString("\n\theader := make(http.Header)\n")
+		for _, h := range r.headers {
+			name, value := splitHeader(h)
+			fmt.Fprintf(b, "\theader.Set(%q, %q)\n", name, value)
+		}
+		b.WriteString("\tctx, err := twirp.WithHTTPRequestHeaders(ctx, header)\n\tif err != nil {\n\t\tlog.Fatal(err)\n\t}\n")
+	}
+
+	fmt.Fprintf(b, "\n\treq := new(%s.%s)\n", messagePkg, r.method.Input.GoIdent.GoName)
+	fmt.Fprintf(b, "\tif err := protojson.Unmarshal([]byte(%s), req); err != nil {\n\t\tlog.Fatal(err)\n\t}\n",
+		goStringLiteral(r.body))
+	fmt.Fprintf(b, "\n\tresp, err := client.%s(ctx, req)\n\tif err != nil {\n\t\tlog.Fatal(err)\n\t}\n", r.method.GoName)
+	b.WriteString("\tlog.Println(protojson.Format(resp))\n}")
+
+	return b.String()
+}
+
+// typeScriptSample returns the fetch call posting the JSON body.
+func typeScriptSample(r *sampleRequest) string {
+	b := new(strings.Builder)
+	fmt.Fprintf(b, "const response = await fetch(%s, {\n", strconv.Quote(r.url))
+	b.WriteString("  method: \"POST\",\n  headers: {\n")
+	fmt.Fprintf(b, "    \"Content-Type\": %q,\n", contentTypeJSON)
+	for _, h := range r.headers {
+		name, value := splitHeader(h)
+		fmt.Fprintf(b, "    %q: %q,\n", name, value)
+	}
+	b.WriteString("  },\n")
+	fmt.Fprintf(b, "  body: JSON.stringify(%s),\n", r.body)
+	b.WriteString("});\nif (!response.ok) {\n  throw new Error((await response.json()).msg);\n}\n")
+	b.WriteString("const data = await response.json();")
+	return b.String()
+}
+
+// pythonSample returns the requests call posting the JSON body.
+func pythonSample(r *sampleRequest) string {
+	b := new(strings.Builder)
+	b.WriteString("import requests\n\nresponse = requests.post(\n")
+	fmt.Fprintf(b, "    %q,\n", r.url)
+	fmt.Fprintf(b, "    headers={\n        \"Content-Type\": %q,\n", contentTypeJSON)
+	for _, h := range r.headers {
+		name, value := splitHeader(h)
+		fmt.Fprintf(b, "        %q: %q,\n", name, value)
+	}
+	b.WriteString("    },\n")
+	fmt.Fprintf(b, "    data=%s,\n", pythonStringLiteral(r.body))
+	b.WriteString(")\nresponse.raise_for_status()\ndata = response.json()")
+	return b.String()
+}
+
+// goPackage returns the Go import path and package name of the file declared by the go_package option.
+func goPackage(file protoreflect.FileDescriptor) (string, string) {
+	importPath := path.Dir(file.Path())
+	if opts, ok := file.Options().(*descriptorpb.FileOptions); ok && opts.GetGoPackage() != "" {
+		importPath = opts.GetGoPackage()
+	}
+
+	if i := strings.Index(importPath, ";"); i >= 0 {
+		return importPath[:i], importPath[i+1:]
+	}
+	return importPath, goSanitizedName(path.Base(importPath))
+}
+
+// goSanitizedName returns the valid Go identifier made of the name.
+func goSanitizedName(name string) string {
+	s := []rune(name)
+	for i, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			s[i] = '_'
+		}
+	}
+	if len(s) == 0 || unicode.IsDigit(s[0]) {
+		return "_" + string(s)
+	}
+	return string(s)
+}
+
+// goStringLiteral returns the raw string literal of s if possible, otherwise the interpreted one.
+func goStringLiteral(s string) string {
+	if strings.Contains(s, "`") {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
+
+// pythonStringLiteral returns the single quoted Python string literal of s.
+func pythonStringLiteral(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
+
+func splitHeader(h string) (string, string) {
+	name, value, _ := splitPair(h, ":")
+	return name, value
+}
+
+func shellSingleQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func shellDoubleQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "`", "\\`").Replace(s) + `"`
+}
+
+// messageJSONCompact returns the single line JSON of the message.
+func messageJSONCompact(m protoreflect.Message) (string, error) {
+	b, err := protojson.Marshal(m.Interface())
+	if err != nil {
+		return "", err
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if err := json.Compact(buf, b); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,108 @@
+package doc
+
+import (
+	"go/format"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func TestCurlSample_Quoting(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "curl -X POST 'https://example.com/twirp/a.B/C' \\\n"+
+		"  -H 'Content-Type: application/json' \\\n"+
+		"  -H \"X-Note: \\\"$NOTE\\\"\" \\\n"+
+		"  -d '{\"name\":\"O'\\''Brien\"}'",
+		curlSample(&sampleRequest{
+			url:     "https://example.com/twirp/a.B/C",
+			headers: []string{`X-Note: "$NOTE"`},
+			body:    `{"name":"O'Brien"}`,
+		}))
+}
+
+func TestGoSample_Parses(t *testing.T) {
+	t.Parallel()
+
+	plugin, err := protogen.Options{}.New(&pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{"test/v1/sample.proto"},
+		ProtoFile: []*descriptorpb.FileDescriptorProto{
+			{
+				Name:        proto.String("test/v1/types.proto"),
+				Package:     proto.String("test.v1"),
+				Syntax:      proto.String("proto3"),
+				Options:     &descriptorpb.FileOptions{GoPackage: proto.String("example.com/test/v1/types;typesv1")},
+				MessageType: []*descriptorpb.DescriptorProto{{Name: proto.String("GetRequest")}},
+			},
+			{
+				Name:       proto.String("test/v1/sample.proto"),
+				Package:    proto.String("test.v1"),
+				Syntax:     proto.String("proto3"),
+				Dependency: []string{"test/v1/types.proto"},
+				Options:    &descriptorpb.FileOptions{GoPackage: proto.String("example.com/test/v1;testv1")},
+				Service: []*descriptorpb.ServiceDescriptorProto{{
+					Name: proto.String("SampleService"),
+					Method: []*descriptorpb.MethodDescriptorProto{{
+						Name:       proto.String("Get"),
+						InputType:  proto.String(".test.v1.GetRequest"),
+						OutputType: proto.String(".test.v1.GetRequest"),
+					}},
+				}},
+			},
+		},
+	})
+	require.NoError(t, err)
+	service := plugin.Files[1].Services[0]
+
+	tests := map[string]struct {
+		baseURL string
+		headers []string
+		imports []string
+	}{
+		"default prefix": {
+			baseURL: "https://example.com/twirp",
+			imports: []string{`"context"`, `"log"`, `"net/http"`, `"google.golang.org/protobuf/encoding/protojson"`,
+				`"example.com/test/v1"`, `"example.com/test/v1/types"`},
+		},
+		"custom prefix with headers": {
+			baseURL: "https://example.com/rpc",
+			headers: []string{"Authorization: Bearer token"},
+			imports: []string{`"context"`, `"log"`, `"net/http"`, `"github.com/twitchtv/twirp"`,
+				`"google.golang.org/protobuf/encoding/protojson"`, `"example.com/test/v1"`, `"example.com/test/v1/types"`},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			code := goSample(&sampleRequest{
+				baseURL: tt.baseURL,
+				service: service,
+				method:  service.Methods[0],
+				headers: tt.headers,
+				body:    `{"id":"foo"}`,
+			})
+
+			f, err := parser.ParseFile(token.NewFileSet(), "main.go", code, parser.AllErrors)
+			require.NoError(t, err, code)
+
+			imports := make([]string, 0, len(f.Imports))
+			for _, spec := range f.Imports {
+				imports = append(imports, spec.Path.Value)
+			}
+			require.Equal(t, tt.imports, imports)
+
+			formatted, err := format.Source([]byte(code))
+			require.NoError(t, err)
+			require.Equal(t, string(formatted), code+"\n")
+		})
+	}
+}
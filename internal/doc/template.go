@@ -0,0 +1,123 @@
+package doc
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	md "github.com/albenik/twirp-doc-gen/internal/markdown"
+)
+
+const templateName = "document"
+
+// templateData is the data of the document template.
+type templateData struct {
+	*Document
+	// Service is the service of the service document, nil in the combined document.
+	Service *Service
+}
+
+// generateTemplate writes the document rendered with the user template instead of the built-in layout.
+func (g *Generator) generateTemplate(d *Document) error {
+	t, err := template.New(templateName).Funcs(g.templateFuncs()).Parse(g.opts.Template)
+	if err != nil {
+		return err
+	}
+
+	data := &templateData{Document: d}
+	if !d.Combined {
+		data.Service = d.Services[0]
+	}
+	return t.Execute(g.writer, data)
+}
+
+// templateFuncs returns the template helpers. The helpers return the Markdown rendered the same way
+// as the built-in layout without the trailing newline.
+func (g *Generator) templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"anchor": md.HeaderAnchor,
+		"escape": md.Escape,
+		"code": func(s string) (string, error) {
+			return renderBlock(md.Code(s))
+		},
+		"codeBlock": func(code, syntax string) (string, error) {
+			if syntax == "" {
+				return renderBlock(md.CodeBlock(code))
+			}
+			return renderBlock(md.CodeBlock(code, syntax))
+		},
+		"link": func(href, label string) (string, error) {
+			return renderBlock(md.Link(href, label))
+		},
+		"comments": func(c *Comments) (string, error) {
+			if c == nil {
+				return "", nil
+			}
+			return renderBlock(g.commentsBlock(c))
+		},
+		"typeLabel": func(t *Type) (string, error) {
+			return renderBlock(typeBlock(t))
+		},
+		"fieldsTable": func(m *Message) (string, error) {
+			return renderBlock(g.fieldsTable(m))
+		},
+		"enumTable": func(e *Enum) (string, error) {
+			return renderBlock(g.enumTable(e))
+		},
+		"examples": func(m *Message) (string, error) {
+			return g.render(func() error {
+				if err := g.printMessageExamples(m); err != nil {
+					return fmt.Errorf("message %s: %w", m.FullName, err)
+				}
+				return nil
+			})
+		},
+		"errors": func(m *Method) (string, error) {
+			return g.render(func() error {
+				return g.printMethodErrors(m.Errors)
+			})
+		},
+		"samples": func(m *Method) (string, error) {
+			return g.render(func() error {
+				g.printSamples(m.Samples)
+				return nil
+			})
+		},
+		"twirpErrors": func() (string, error) {
+			return g.render(func() error {
+				g.printErrors()
+				return nil
+			})
+		},
+	}
+}
+
+// render returns the Markdown of the blocks printed by the function.
+func (g *Generator) render(fn func() error) (string, error) {
+	doc := g.doc
+	defer func() { g.doc = doc }()
+
+	g.doc = new(md.Document)
+	if err := fn(); err != nil {
+		return "", err
+	}
+
+	buf := new(strings.Builder)
+	if err := g.doc.Generate(buf); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// renderBlock returns the Markdown of the block, the empty string for the nil one.
+func renderBlock(b md.Block) (string, error) {
+	if b == nil {
+		return "", nil
+	}
+
+	buf := new(strings.Builder)
+	if err := b.Markdown(buf); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
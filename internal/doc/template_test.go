@@ -0,0 +1,93 @@
+package doc_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/compiler/protogen"
+
+	"github.com/albenik/twirp-doc-gen/internal/doc"
+)
+
+func templateOptions(t *testing.T) *doc.Options {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "service.md.tmpl"))
+	require.NoError(t, err)
+
+	opts := testOptions()
+	opts.Template = string(data)
+	return opts
+}
+
+func TestGenerator_Template(t *testing.T) {
+	t.Parallel()
+
+	out := generateServiceDocumentWithOptions(t, "models.textproto", "UserService", templateOptions(t))
+	require.Contains(t, out, "---\ntitle: UserService\n---\n\n# UserService API\n")
+	require.Contains(t, out, "Base URL: `"+testBaseURL+"/test.v1.UserService`\n")
+	require.Contains(t, out, "## Get_User\n\n`POST "+testBaseURL+"/test.v1.UserService/Get_User`\n")
+	require.Contains(t, out, "### Request `test.v1.GetUserRequest`\n\n```json\n")
+	require.Contains(t, out, "| `roles`   |           array of [test.v1.Role](#testv1role)            |             |\n")
+	require.Contains(t, out, "| `labels`  | map string to [test.v1.User_Profile](#testv1user_profile) |             |\n")
+	require.Contains(t, out, "## test.v1.Role\n\n"+
+		"| Value              | Description |\n"+
+		"|:-------------------|:------------|\n"+
+		"| `ROLE_UNSPECIFIED` |             |\n"+
+		"| `ROLE_ADMIN`       |             |\n")
+	require.Contains(t, out, "## Twirp Errors\n\n[Official documentation]")
+	require.JSONEq(t, `{"id": "foo"}`, jsonExamples(out)[0])
+}
+
+func TestGenerator_TemplateErrors(t *testing.T) {
+	t.Parallel()
+
+	out := generateServiceDocumentWithOptions(t, "errors.textproto", "UserService", templateOptions(t))
+	require.Contains(t, out, "## Get\n\n`POST "+testBaseURL+"/test.v1.UserService/Get`\n\n*Returns the user.*\n")
+	require.Contains(t, out, "#### Errors\n\n| Twirp Error Code    | HTTP Status | Description             |\n")
+	require.Contains(t, out, "Example of the `invalid_argument` error response body:\n\n```json\n")
+	require.NotContains(t, out, "@error")
+}
+
+func TestGenerator_TemplateHelpers(t *testing.T) {
+	t.Parallel()
+
+	opts := testOptions()
+	opts.Errors = doc.ErrorsLink
+	opts.ErrorsURL = "errors.md"
+	opts.Samples = []string{doc.SampleCurl}
+	opts.Template = `{{ range .Services }}{{ link (anchor .FullName) .Name }}{{ range .Methods }} {{ escape .Name }}
+{{ samples . }}{{ end }}{{ end }}
+{{ codeBlock "a" "" }}
+{{ twirpErrors }}
+`
+
+	plugin := loadPlugin(t, "models.textproto")
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, doc.NewGenerator(buf, opts).
+		GenerateDocument("API", []*protogen.Service{findService(t, plugin, "UserService")}))
+	require.Equal(t, "[UserService](#testv1userservice) Get\\_User\n"+
+		"#### Samples\n\n```sh\ncurl -X POST '"+testBaseURL+"/test.v1.UserService/Get_User' \\\n"+
+		"  -H 'Content-Type: application/json' \\\n  -d '{\"id\":\"foo\"}'\n```\n"+
+		"```\na\n```\n"+
+		"## Twirp Errors\n\nSee [Twirp error codes](errors.md).\n", buf.String())
+}
+
+func TestGenerator_TemplateFailures(t *testing.T) {
+	t.Parallel()
+
+	opts := testOptions()
+	opts.Template = "{{ .Unknown "
+	err := doc.NewGenerator(bytes.NewBuffer(nil), opts).
+		GenerateServiceDocument(findService(t, loadPlugin(t, "models.textproto"), "UserService"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "template: document:1:")
+
+	err = doc.NewGenerator(bytes.NewBuffer(nil), templateOptions(t)).
+		GenerateServiceDocument(findService(t, loadPlugin(t, "examples.textproto"), "BadService"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `error calling examples: message test.v1.BadRequest: field test.v1.BadRequest.age: invalid example "*forty_two*": `)
+}
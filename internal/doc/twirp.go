@@ -0,0 +1,214 @@
+package doc
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+const twirpErrorCodesURL = "https://twitchtv.github.io/twirp/docs/spec_v7.html#error-codes"
+
+// ErrorCode is the standard Twirp error code.
+type ErrorCode struct {
+	Code        string `json:"code"`
+	HTTPStatus  int    `json:"httpStatus"`
+	Description string `json:"description"`
+}
+
+// twirpErrorCodes lists all Twirp error codes in order of their HTTP statuses.
+var twirpErrorCodes = []*ErrorCode{
+	{
+		Code:       "invalid_argument",
+		HTTPStatus: http.StatusBadRequest,
+		Description: "The client specified an invalid argument. " +
+			"This indicates arguments that are invalid regardless of the state of the system " +
+			"(i.e. a malformed file name, required argument, number out of range, etc.).",
+	},
+	{
+		Code:       "malformed",
+		HTTPStatus: http.StatusBadRequest,
+		Description: "The client sent a message which could not be decoded. " +
+			"This may mean that the message was encoded improperly or that the client and server have incompatible " +
+			"message definitions.",
+	},
+	{
+		Code:       "out_of_range",
+		HTTPStatus: http.StatusBadRequest,
+		Description: "The operation was attempted past the valid range. " +
+			"For example, seeking or reading past end of a paginated collection. Unlike \"invalid_argument\", " +
+			"this error indicates a problem that may be fixed if the system state changes " +
+			"(i.e. adding more items to the collection). There is a fair bit of overlap between " +
+			"\"failed_precondition\" and \"out_of_range\". We recommend using \"out_of_range\" " +
+			"(the more specific error) when it applies so that callers who are iterating through a space" +
+			" can easily look for an \"out_of_range\" error to detect when they are done.",
+	},
+	{
+		Code:       "unauthenticated",
+		HTTPStatus: http.StatusUnauthorized,
+		Description: "The request does not have valid authentication " +
+			"credentials for the operation.",
+	},
+	{
+		Code:       "permission_denied",
+		HTTPStatus: http.StatusForbidden,
+		Description: "The caller does not have permission to execute " +
+			"the specified operation. It must not be used if the caller cannot be identified " +
+			"(use \"unauthenticated\" instead).",
+	},
+	{
+		Code:       "bad_route",
+		HTTPStatus: http.StatusNotFound,
+		Description: "The requested URL path wasn't routable to a Twirp " +
+			"service and method. This is returned by generated server code and should not be returned by " +
+			"application code (use \"not_found\" or \"unimplemented\" instead).",
+	},
+	{
+		Code:        "not_found",
+		HTTPStatus:  http.StatusNotFound,
+		Description: "Some requested entity was not found.",
+	},
+	{
+		Code:        "canceled",
+		HTTPStatus:  http.StatusRequestTimeout,
+		Description: "The operation was cancelled.",
+	},
+	{
+		Code:       "deadline_exceeded",
+		HTTPStatus: http.StatusRequestTimeout,
+		Description: "Operation expired before completion. " +
+			"For operations that change the state of the system, this error may be returned even if the operation " +
+			"has completed successfully (timeout).",
+	},
+	{
+		Code:       "already_exists",
+		HTTPStatus: http.StatusConflict,
+		Description: "An attempt to create an entity failed because one " +
+			"already exists.",
+	},
+	{
+		Code:       "aborted",
+		HTTPStatus: http.StatusConflict,
+		Description: "The operation was aborted, typically due to " +
+			"a concurrency issue like sequencer check failures, transaction aborts, etc.",
+	},
+	{
+		Code:       "failed_precondition",
+		HTTPStatus: http.StatusPreconditionFailed,
+		Description: "The operation was rejected because the " +
+			"system is not in a state required for the operation's execution. For example, doing an rmdir " +
+			"operation on a directory that is non-empty, or on a non-directory object, or when having conflicting " +
+			"read-modify-write on the same resource.",
+	},
+	{
+		Code:       "resource_exhausted",
+		HTTPStatus: http.StatusTooManyRequests,
+		Description: "Some resource has been exhausted or " +
+			"rate-limited, perhaps a per-user quota, or perhaps the entire file system is out of space.",
+	},
+	{
+		Code:       "unknown",
+		HTTPStatus: http.StatusInternalServerError,
+		Description: "An unknown error occurred. For example, " +
+			"this can be used when handling errors raised by APIs that do not return any error information.",
+	},
+	{
+		Code:       "internal",
+		HTTPStatus: http.StatusInternalServerError,
+		Description: "When some invariants expected by the underlying system " +
+			"have been broken. In other words, something bad happened in the library or backend service. " +
+			"Twirp specific issues like wire and serialization problems are also reported as \"internal\" errors.",
+	},
+	{
+		Code:       "dataloss",
+		HTTPStatus: http.StatusInternalServerError,
+		Description: "The operation resulted in unrecoverable data loss or " +
+			"corruption.",
+	},
+	{
+		Code:       "unimplemented",
+		HTTPStatus: http.StatusNotImplemented,
+		Description: "The operation is not implemented or not " +
+			"supported/enabled in this service.",
+	},
+	{
+		Code:       "unavailable",
+		HTTPStatus: http.StatusServiceUnavailable,
+		Description: "The service is currently unavailable. This is most " +
+			"likely a transient condition and may be corrected by retrying with a backoff.",
+	},
+}
+
+// ErrorSpec is the Twirp error declared for the method.
+type ErrorSpec struct {
+	Code        string       `json:"code"`
+	HTTPStatus  int          `json:"httpStatus"`
+	Description string       `json:"description,omitempty"`
+	Meta        []*ErrorMeta `json:"meta,omitempty"`
+}
+
+// ErrorMeta is the key of the Twirp error meta map declared for the method error.
+type ErrorMeta struct {
+	Key         string `json:"key"`
+	Description string `json:"description,omitempty"`
+}
+
+// copyTwirpErrorCodes returns the copy of the Twirp error codes the document may expose
+// without sharing the global table.
+func copyTwirpErrorCodes() []*ErrorCode {
+	codes := make([]*ErrorCode, 0, len(twirpErrorCodes))
+	for _, c := range twirpErrorCodes {
+		c := *c
+		codes = append(codes, &c)
+	}
+	return codes
+}
+
+// twirpErrorCodeByName returns the Twirp error code or nil if the code is unknown.
+func twirpErrorCodeByName(code string) *ErrorCode {
+	for _, c := range twirpErrorCodes {
+		if c.Code == code {
+			return c
+		}
+	}
+	return nil
+}
+
+// twirpError is the JSON body of the Twirp error response.
+type twirpError struct {
+	Code string            `json:"code"`
+	Msg  string            `json:"msg"`
+	Meta map[string]string `json:"meta,omitempty"`
+}
+
+// exampleError returns the method error shown in the example, the first one declaring the meta keys if any.
+func exampleError(errs []*ErrorSpec) *ErrorSpec {
+	for _, e := range errs {
+		if len(e.Meta) > 0 {
+			return e
+		}
+	}
+	return errs[0]
+}
+
+// errorJSONFormat returns the example JSON body of the Twirp error response. The meta values are the key
+// descriptions, the key in angle brackets if the description is not declared.
+func errorJSONFormat(e *ErrorSpec) (string, error) {
+	body := &twirpError{Code: e.Code, Msg: e.Description}
+	if body.Msg == "" {
+		body.Msg = e.Code
+	}
+	if len(e.Meta) > 0 {
+		body.Meta = make(map[string]string, len(e.Meta))
+		for _, m := range e.Meta {
+			body.Meta[m.Key] = m.Description
+			if m.Description == "" {
+				body.Meta[m.Key] = "<" + m.Key + ">"
+			}
+		}
+	}
+
+	b, err := json.MarshalIndent(body, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
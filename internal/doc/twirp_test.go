@@ -0,0 +1,34 @@
+package doc
+
+import (
+	"net/http"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorJSONFormat_Meta(t *testing.T) {
+	t.Parallel()
+
+	body, err := errorJSONFormat(&ErrorSpec{
+		Code: "not_found",
+		Meta: []*ErrorMeta{{Key: "user_id", Description: "the id of the missing user"}, {Key: "trace_id"}},
+	})
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"code": "not_found",
+		"msg": "not_found",
+		"meta": {"user_id": "the id of the missing user", "trace_id": "<trace_id>"}
+	}`, body)
+}
+
+func TestTwirpErrorCodes_Statuses(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, http.StatusServiceUnavailable, twirpErrorCodeByName("unavailable").HTTPStatus)
+	require.Equal(t, http.StatusInternalServerError, twirpErrorCodeByName("dataloss").HTTPStatus)
+	require.True(t, sort.SliceIsSorted(twirpErrorCodes, func(i, j int) bool {
+		return twirpErrorCodes[i].HTTPStatus < twirpErrorCodes[j].HTTPStatus
+	}))
+}
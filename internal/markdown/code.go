@@ -1,15 +1,33 @@
 package markdown
 
+import (
+	"strings"
+)
+
+const minCodeBlockFence = 3
+
+// Code renders an inline code span. The fence is longer than any backtick run of the code.
 func Code(code string) Block {
-	return Wrap([]byte("`"), []byte("`"), T(code))
+	fence := strings.Repeat("`", longestRun(code, '`')+1)
+	if strings.HasPrefix(code, "`") || strings.HasSuffix(code, "`") {
+		code = " " + code + " "
+	}
+	return Wrap([]byte(fence), []byte(fence), T(code))
 }
 
+// CodeBlock renders a fenced code block. The fence is longer than any backtick run of the code.
 func CodeBlock(code string, syntax ...string) Block {
+	n := longestRun(code, '`') + 1
+	if n < minCodeBlockFence {
+		n = minCodeBlockFence
+	}
+	fence := strings.Repeat("`", n)
+
 	var prefix []byte
 	if len(syntax) != 0 {
-		prefix = []byte("```" + syntax[0] + "\n")
+		prefix = []byte(fence + syntax[0] + "\n")
 	} else {
-		prefix = []byte("```\n")
+		prefix = []byte(fence + "\n")
 	}
-	return Wrap(prefix, []byte("\n```\n"), T(code))
+	return Wrap(prefix, []byte("\n"+fence+"\n"), T(code))
 }
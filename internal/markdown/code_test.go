@@ -24,3 +24,21 @@ func TestCodeBlock_Markdown(t *testing.T) {
 	require.NoError(t, markdown.CodeBlock("{\n  foo = bar\n}").Markdown(buf))
 	require.Equal(t, "```\n{\n  foo = bar\n}\n```\n", buf.String())
 }
+
+func TestCode_Fences(t *testing.T) {
+	t.Parallel()
+
+	runTestCases(t, []*testCase{{
+		Name:   "InlineWithBacktick",
+		Block:  markdown.Code("a`b"),
+		Result: "``a`b``",
+	}, {
+		Name:   "InlineStartsWithBacktick",
+		Block:  markdown.Code("`a``"),
+		Result: "``` `a`` ```",
+	}, {
+		Name:   "BlockWithFence",
+		Block:  markdown.CodeBlock("```go\nfoo\n```", "md"),
+		Result: "````md\n```go\nfoo\n```\n````\n",
+	}})
+}
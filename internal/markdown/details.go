@@ -0,0 +1,11 @@
+package markdown
+
+// Details renders the collapsible section with the summary. The content is separated by empty lines
+// to be rendered as Markdown.
+func Details(summary string, blocks ...Block) Block {
+	return G(
+		T("<details>\n<summary>"+summary+"</summary>\n"),
+		G(blocks...),
+		T("</details>\n"),
+	)
+}
@@ -0,0 +1,21 @@
+package markdown_test
+
+import (
+	"testing"
+
+	md "github.com/albenik/twirp-doc-gen/internal/markdown"
+)
+
+func TestDetails_Markdown(t *testing.T) {
+	t.Parallel()
+
+	runTestCases(t, []*testCase{{
+		Name:   "Paragraph",
+		Block:  md.Details("Summary", md.TP("Test OK")),
+		Result: "<details>\n<summary>Summary</summary>\n\nTest OK\n\n</details>\n",
+	}, {
+		Name:   "MultiParagraph",
+		Block:  md.Details("Summary", md.TP("P#1"), md.TP("P#2")),
+		Result: "<details>\n<summary>Summary</summary>\n\nP#1\n\nP#2\n\n</details>\n",
+	}})
+}
@@ -0,0 +1,105 @@
+package markdown
+
+import (
+	"bytes"
+	"strings"
+)
+
+// inlineSpecials are escaped everywhere in the text.
+const inlineSpecials = "\\`*_[]<>|~"
+
+// lineStartSpecials are escaped only at the beginning of the line where they start a block.
+const lineStartSpecials = "#>-+="
+
+// Escape escapes the Markdown syntax characters so the text is rendered literally.
+func Escape(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	lineStart := true
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		switch {
+		case strings.IndexByte(inlineSpecials, c) >= 0:
+			b.WriteByte('\\')
+		case lineStart && strings.IndexByte(lineStartSpecials, c) >= 0:
+			b.WriteByte('\\')
+		case lineStart && c >= '0' && c <= '9':
+			// ordered list item marker "1." or "1)"
+			j := i
+			for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+				j++
+			}
+			b.WriteString(s[i:j])
+			if j < len(s) && (s[j] == '.' || s[j] == ')') {
+				b.WriteByte('\\')
+			}
+			i = j - 1
+			lineStart = false
+			continue
+		}
+
+		b.WriteByte(c)
+
+		switch c {
+		case '\n':
+			lineStart = true
+		case ' ', '\t':
+		default:
+			lineStart = false
+		}
+	}
+
+	return b.String()
+}
+
+// E renders an escaped plain text block.
+func E(s string) Block {
+	return T(Escape(s))
+}
+
+// EI renders an escaped italic text block.
+func EI(s string) Block {
+	return I(E(s))
+}
+
+// escapeTableCell escapes the unescaped pipes of the rendered table cell,
+// the pipes are escaped inside code spans too as it required by GFM tables.
+func escapeTableCell(b []byte) []byte {
+	if bytes.IndexByte(b, '|') < 0 {
+		return b
+	}
+
+	res := make([]byte, 0, len(b)+bytes.Count(b, tableColSep))
+	backslashes := 0
+	for _, c := range b {
+		if c == '|' && backslashes%2 == 0 {
+			res = append(res, '\\')
+		}
+		if c == '\\' {
+			backslashes++
+		} else {
+			backslashes = 0
+		}
+		res = append(res, c)
+	}
+
+	return res
+}
+
+// longestRun returns the length of the longest run of the character c in s.
+func longestRun(s string, c byte) int {
+	longest, current := 0, 0
+	for i := 0; i < len(s); i++ {
+		if s[i] != c {
+			current = 0
+			continue
+		}
+		current++
+		if current > longest {
+			longest = current
+		}
+	}
+	return longest
+}
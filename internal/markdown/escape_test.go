@@ -0,0 +1,54 @@
+package markdown_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	md "github.com/albenik/twirp-doc-gen/internal/markdown"
+)
+
+func TestEscape(t *testing.T) {
+	t.Parallel()
+
+	for _, c := range []struct {
+		Name   string
+		Text   string
+		Result string
+	}{
+		{Name: "Plain", Text: "plain text", Result: "plain text"},
+		{Name: "Emphasis", Text: "*bold* and _italic_", Result: `\*bold\* and \_italic\_`},
+		{Name: "Code", Text: "a `code`", Result: "a \\`code\\`"},
+		{Name: "Pipe", Text: "a|b", Result: `a\|b`},
+		{Name: "HTML", Text: "<br/>", Result: `\<br/\>`},
+		{Name: "Link", Text: "[foo](bar)", Result: `\[foo\](bar)`},
+		{Name: "Backslash", Text: `C:\dir`, Result: `C:\\dir`},
+		{Name: "Header", Text: "# not a header", Result: `\# not a header`},
+		{Name: "HeaderInside", Text: "issue #1", Result: "issue #1"},
+		{Name: "List", Text: "- item\n  + item", Result: "\\- item\n  \\+ item"},
+		{Name: "OrderedList", Text: "1. item\n2) item\n2021 year", Result: "1\\. item\n2\\) item\n2021 year"},
+		{Name: "Quote", Text: "> quote", Result: `\> quote`},
+	} {
+		c := c
+
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, c.Result, md.Escape(c.Text))
+		})
+	}
+}
+
+func TestE_Markdown(t *testing.T) {
+	t.Parallel()
+
+	runTestCases(t, []*testCase{{
+		Name:   "Escaped",
+		Block:  md.E("a*b*"),
+		Result: `a\*b\*`,
+	}, {
+		Name:   "EscapedItalic",
+		Block:  md.EI("a*b*"),
+		Result: `*a\*b\**`,
+	}})
+}
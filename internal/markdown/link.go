@@ -4,10 +4,11 @@ import (
 	"fmt"
 	"io"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
-var anchroRx = regexp.MustCompile(`[^a-z0-9\-]`)
+var anchroRx = regexp.MustCompile(`[^a-z0-9\-_]`)
 
 type linkBlock struct {
 	href  string
@@ -21,11 +22,32 @@ func (l *linkBlock) Markdown(w io.Writer) error {
 
 func LinkToHeader(hdr, label string) Block {
 	return &linkBlock{
-		href:  "#" + anchroRx.ReplaceAllString(strings.ReplaceAll(strings.ToLower(hdr), " ", "-"), ""),
+		href:  HeaderAnchor(hdr),
 		label: label,
 	}
 }
 
+// HeaderAnchor returns the "#anchor" reference of the header with the given text.
+func HeaderAnchor(hdr string) string {
+	return "#" + anchroRx.ReplaceAllString(strings.ReplaceAll(strings.ToLower(hdr), " ", "-"), "")
+}
+
+// Anchors generates the unique anchors of the document headers in their order the way GitHub does,
+// the repeated header gets the "-1", "-2", etc. suffix.
+type Anchors map[string]bool
+
+// Anchor returns the "#anchor" reference of the next header with the given text.
+func (a Anchors) Anchor(hdr string) string {
+	base := HeaderAnchor(hdr)
+
+	anchor := base
+	for i := 1; a[anchor]; i++ {
+		anchor = base + "-" + strconv.Itoa(i)
+	}
+	a[anchor] = true
+	return anchor
+}
+
 func Link(href, label string) Block {
 	return &linkBlock{
 		href:  href,
@@ -0,0 +1,39 @@
+package markdown_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	md "github.com/albenik/twirp-doc-gen/internal/markdown"
+)
+
+func TestLink_Markdown(t *testing.T) {
+	t.Parallel()
+
+	runTestCases(t, []*testCase{{
+		Name:   "Link",
+		Block:  md.Link("https://example.com", "Example"),
+		Result: "[Example](https://example.com)",
+	}, {
+		Name:   "ToHeader",
+		Block:  md.LinkToHeader("POST /GetUser", "GetUser"),
+		Result: "[GetUser](#post-getuser)",
+	}, {
+		Name:   "ToHeaderWithFullName",
+		Block:  md.LinkToHeader("test.v1.User_Status", "test.v1.User_Status"),
+		Result: "[test.v1.User_Status](#testv1user_status)",
+	}})
+}
+
+func TestAnchors_Anchor(t *testing.T) {
+	t.Parallel()
+
+	anchors := make(md.Anchors)
+	require.Equal(t, "#methods", anchors.Anchor("Methods"))
+	require.Equal(t, "#testv1user", anchors.Anchor("test.v1.User"))
+	require.Equal(t, "#methods-1", anchors.Anchor("Methods"))
+	require.Equal(t, "#testv1user-1", anchors.Anchor("testv1.User"))
+	require.Equal(t, "#methods-1-1", anchors.Anchor("Methods-1"))
+	require.Equal(t, "#methods-2", anchors.Anchor("Methods"))
+}
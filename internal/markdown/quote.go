@@ -0,0 +1,46 @@
+package markdown
+
+import (
+	"bytes"
+	"io"
+)
+
+var (
+	quotePrefix      = []byte("> ")
+	quoteEmptyPrefix = []byte(">")
+)
+
+type quoteBlock struct {
+	nested Block
+}
+
+// Quote renders a blockquote.
+func Quote(blocks ...Block) Block {
+	return &quoteBlock{nested: G(blocks...)}
+}
+
+func (q *quoteBlock) Markdown(w io.Writer) error {
+	buf := bytes.NewBuffer(nil)
+	if err := q.nested.Markdown(buf); err != nil {
+		return err
+	}
+
+	for _, line := range bytes.Split(bytes.TrimSuffix(buf.Bytes(), newline), newline) {
+		prefix := quotePrefix
+		if len(line) == 0 {
+			prefix = quoteEmptyPrefix
+		}
+
+		if _, err := w.Write(prefix); err != nil {
+			return err
+		}
+		if _, err := w.Write(line); err != nil {
+			return err
+		}
+		if _, err := w.Write(newline); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
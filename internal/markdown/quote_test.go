@@ -0,0 +1,21 @@
+package markdown_test
+
+import (
+	"testing"
+
+	md "github.com/albenik/twirp-doc-gen/internal/markdown"
+)
+
+func TestQuote_Markdown(t *testing.T) {
+	t.Parallel()
+
+	runTestCases(t, []*testCase{{
+		Name:   "Inline",
+		Block:  md.Quote(md.TB("Warning:"), md.T(" text")),
+		Result: "> **Warning:** text\n",
+	}, {
+		Name:   "Paragraphs",
+		Block:  md.Quote(md.TP("P#1"), md.TP("P#2")),
+		Result: "> P#1\n>\n> P#2\n",
+	}})
+}
@@ -87,9 +87,10 @@ func (t *Table) Markdown(w io.Writer) error { //nolint:funlen,gocognit,gocyclo,c
 			if err := col.Markdown(buf); err != nil {
 				return err
 			}
-			cols = append(cols, buf.Bytes())
+			cell := escapeTableCell(buf.Bytes())
+			cols = append(cols, cell)
 
-			if wd := utf8.RuneCount(buf.Bytes()); wd > widths[i] {
+			if wd := utf8.RuneCount(cell); wd > widths[i] {
 				widths[i] = wd
 			}
 		}
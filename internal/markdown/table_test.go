@@ -75,5 +75,19 @@ func TestRenderTable(t *testing.T) {
 			"| `code`   |  `code`  |   `code` |\n" +
 			"| 12345678 | 12345678 | 12345678 |\n" +
 			"| **bold** | *italic* |    plain |\n",
+	}, {
+		Name: "Pipes",
+		Block: func() *md.Table {
+			table := new(md.Table)
+			table.AddColumn("A", md.AlignLeft)
+			table.AddColumn("B", md.AlignLeft)
+			table.AppendRow(md.Code("a|b"), md.T("a|b"))
+			table.AppendRow(md.E("a|b"), md.T(`a\\|b`))
+			return table
+		}(),
+		Result: "| A      | B      |\n" +
+			"|:-------|:-------|\n" +
+			"| `a\\|b` | a\\|b   |\n" +
+			"| a\\|b   | a\\\\\\|b |\n",
 	}})
 }
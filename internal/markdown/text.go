@@ -7,6 +7,7 @@ import (
 var (
 	asterisk   = []byte("*")
 	asteriskX2 = []byte("**")
+	tildeX2    = []byte("~~")
 	hr         = []byte("---\n")
 )
 
@@ -40,6 +41,11 @@ func TB(s string) Block {
 	return B(T(s))
 }
 
+// S renders a strikethrough text block.
+func S(blocks ...Block) Block {
+	return Wrap(tildeX2, tildeX2, blocks...)
+}
+
 // P renders a paragraph.
 func P(blocks ...Block) Block {
 	return Wrap(nil, newline, blocks...)
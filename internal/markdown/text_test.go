@@ -21,6 +21,10 @@ func TestText_Markdown(t *testing.T) {
 		Name:   "Bold",
 		Block:  md.B(md.T("Test OK")),
 		Result: "**Test OK**",
+	}, {
+		Name:   "Strikethrough",
+		Block:  md.S(md.Code("Test OK")),
+		Result: "~~`Test OK`~~",
 	}, {
 		Name:   "CombinedFormatting",
 		Block:  md.B(md.TI("Test"), md.T(" OK")),
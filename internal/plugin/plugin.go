@@ -0,0 +1,356 @@
+// Package plugin implements the documentation generation shared by the protoc plugin and the standalone command.
+package plugin
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/types/pluginpb"
+
+	"github.com/albenik/twirp-doc-gen/internal/doc"
+	"github.com/albenik/twirp-doc-gen/internal/site"
+)
+
+const (
+	formatMarkdown = "markdown"
+	formatOpenAPI  = "openapi"
+	formatHTML     = "html"
+	formatJSON     = "json"
+
+	layoutService = "service"
+	layoutPackage = "package"
+	layoutSingle  = "single"
+
+	singleDocumentName  = "api"
+	singleDocumentTitle = "API Reference"
+
+	indexDocumentName  = "index.md"
+	errorsDocumentName = "twirp_errors.md"
+)
+
+// Plugin holds the generation options parsed from the plugin parameter.
+type Plugin struct {
+	opts            *doc.Options
+	flags           *flag.FlagSet
+	curl            *bool
+	samples         *string
+	encodings       *string
+	headers         *string
+	format          *string
+	layout          *string
+	openAPIEncoding *string
+	index           *bool
+	template        *string
+}
+
+// document is a set of services generated into the single output file.
+type document struct {
+	source     string
+	prefix     string
+	title      string
+	services   []*protogen.Service
+	importPath protogen.GoImportPath
+	combined   bool
+}
+
+// New returns the plugin with the default options.
+func New() *Plugin {
+	p := &Plugin{
+		opts:  new(doc.Options),
+		flags: new(flag.FlagSet),
+	}
+
+	p.flags.StringVar(&p.opts.BaseURL, "base_url", "https://api.example.com/twirp", "")
+	p.flags.StringVar(&p.opts.Streaming, "streaming", doc.StreamingWarn, "")
+	p.flags.StringVar(&p.opts.InvalidExamples, "invalid_examples", doc.InvalidExamplesError, "")
+	p.flags.BoolVar(&p.opts.RawComments, "raw_comments", false, "")
+	p.flags.StringVar(&p.opts.Comments, "comments", doc.CommentsItalic, "")
+	p.flags.BoolVar(&p.opts.DetachedComments, "detached_comments", false, "")
+	p.flags.StringVar(&p.opts.Deprecated, "deprecated", doc.DeprecatedMark, "")
+	p.flags.StringVar(&p.opts.BinaryDump, "binary_dump", doc.BinaryDumpHex, "")
+	p.flags.StringVar(&p.opts.Errors, "errors", doc.ErrorsFull, "")
+	p.flags.StringVar(&p.opts.ErrorsURL, "errors_url", "", "")
+	p.curl = p.flags.Bool("curl", false, "")
+	p.samples = p.flags.String("samples", "", "")
+	p.encodings = p.flags.String("encodings", doc.ContentJSON, "")
+	p.headers = p.flags.String("headers", "", "")
+	p.format = p.flags.String("format", formatMarkdown, "")
+	p.layout = p.flags.String("layout", layoutService, "")
+	p.openAPIEncoding = p.flags.String("openapi_encoding", doc.EncodingYAML, "")
+	p.index = p.flags.Bool("index", false, "")
+	p.template = p.flags.String("template", "", "")
+
+	return p
+}
+
+// Set sets the option parsed from the plugin parameter, it is used as the protogen.Options.ParamFunc.
+func (p *Plugin) Set(name, value string) error {
+	return p.flags.Set(name, value)
+}
+
+// Generate generates the documents of the files to generate.
+func (p *Plugin) Generate(plugin *protogen.Plugin) error { //nolint:funlen,gocognit,cyclop
+	opts := p.opts
+
+	// the shared errors document is generated if the link mode has no explicit URL
+	sharedErrors := opts.Errors == doc.ErrorsLink && opts.ErrorsURL == ""
+	if sharedErrors {
+		opts.ErrorsURL = errorsDocumentName
+	}
+
+	opts.Encodings = strings.Split(*p.encodings, "+")
+	if *p.samples != "" {
+		opts.Samples = strings.Split(*p.samples, "+")
+	}
+	if *p.curl && !contains(opts.Samples, doc.SampleCurl) {
+		opts.Samples = append(opts.Samples, doc.SampleCurl)
+	}
+	if *p.headers != "" {
+		opts.Headers = strings.Split(*p.headers, "+")
+	}
+	if *p.template != "" {
+		data, err := os.ReadFile(*p.template)
+		if err != nil {
+			return fmt.Errorf("template: %w", err)
+		}
+		opts.Template = string(data)
+	}
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	formats := strings.Split(*p.format, "+")
+	markdown, html := false, false
+	for _, f := range formats {
+		switch f {
+		case formatMarkdown:
+			markdown = true
+		case formatHTML:
+			html = true
+		case formatOpenAPI, formatJSON:
+		default:
+			return fmt.Errorf("unknown format %q", f)
+		}
+	}
+	if *p.index && !markdown && !html {
+		return fmt.Errorf("index requires %q or %q format", formatMarkdown, formatHTML)
+	}
+
+	docs, err := documents(plugin, *p.layout, opts)
+	if err != nil {
+		return err
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+
+	var pages []*site.Page
+	indexDocs := make([]*doc.IndexDocument, 0, len(docs))
+	for _, d := range docs {
+		indexDocs = append(indexDocs, &doc.IndexDocument{
+			Path:     filepath.ToSlash(d.prefix + ".md"),
+			Services: d.services,
+			Combined: d.combined,
+		})
+
+		docOpts := opts
+		if sharedErrors {
+			docOpts = d.relativeErrorsOptions(opts)
+		}
+
+		for _, f := range formats {
+			var err error
+
+			switch f {
+			case formatMarkdown:
+				err = d.generateMarkdown(plugin.NewGeneratedFile(d.prefix+".md", d.importPath), docOpts)
+			case formatHTML:
+				buf := bytes.NewBuffer(nil)
+				err = d.generateMarkdown(buf, docOpts)
+				pages = append(pages, &site.Page{
+					Path:     site.PagePath(filepath.ToSlash(d.prefix + ".md")),
+					Title:    d.title,
+					Markdown: buf.String(),
+				})
+			case formatOpenAPI:
+				gf := plugin.NewGeneratedFile(d.prefix+".openapi."+*p.openAPIEncoding, d.importPath)
+				g := doc.NewGenerator(gf, opts)
+				if d.combined {
+					err = g.GenerateOpenAPI(d.title, d.services, *p.openAPIEncoding)
+				} else {
+					err = g.GenerateServiceOpenAPI(d.services[0], *p.openAPIEncoding)
+				}
+			case formatJSON:
+				g := doc.NewGenerator(plugin.NewGeneratedFile(d.prefix+".json", d.importPath), docOpts)
+				if d.combined {
+					err = g.GenerateJSON(d.title, d.services)
+				} else {
+					err = g.GenerateServiceJSON(d.services[0])
+				}
+			}
+
+			if err != nil {
+				return fmt.Errorf("%s: schema: %w", d.source, err)
+			}
+		}
+	}
+
+	if sharedErrors && (markdown || html) {
+		buf := bytes.NewBuffer(nil)
+		if err := doc.NewGenerator(buf, opts).GenerateErrors(); err != nil {
+			return fmt.Errorf("%s: %w", errorsDocumentName, err)
+		}
+		if markdown {
+			_, _ = plugin.NewGeneratedFile(errorsDocumentName, docs[0].importPath).Write(buf.Bytes())
+		}
+		pages = append(pages, &site.Page{Path: site.PagePath(errorsDocumentName), Title: "Twirp Errors", Markdown: buf.String()})
+	}
+
+	// the site is always browsed from the index page
+	if *p.index || html {
+		buf := bytes.NewBuffer(nil)
+		if err := doc.NewGenerator(buf, opts).GenerateIndex(singleDocumentTitle, indexDocs); err != nil {
+			return fmt.Errorf("%s: %w", indexDocumentName, err)
+		}
+		if *p.index && markdown {
+			_, _ = plugin.NewGeneratedFile(indexDocumentName, docs[0].importPath).Write(buf.Bytes())
+		}
+		pages = append([]*site.Page{{
+			Path:     site.PagePath(indexDocumentName),
+			Title:    singleDocumentTitle,
+			Markdown: buf.String(),
+		}}, pages...)
+	}
+
+	if html {
+		for _, page := range pages {
+			if err := site.Write(plugin.NewGeneratedFile(page.Path, docs[0].importPath), page, pages); err != nil {
+				return fmt.Errorf("%s: %w", page.Path, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// generateMarkdown writes the Markdown document of the services.
+func (d *document) generateMarkdown(w io.Writer, opts *doc.Options) error {
+	g := doc.NewGenerator(w, opts)
+	if d.combined {
+		return g.GenerateDocument(d.title, d.services)
+	}
+	return g.GenerateServiceDocument(d.services[0])
+}
+
+// Execute runs the plugin on the request the way protoc does and returns the generated files.
+func (p *Plugin) Execute(req *pluginpb.CodeGeneratorRequest) (*pluginpb.CodeGeneratorResponse, error) {
+	gen, err := protogen.Options{ParamFunc: p.Set}.New(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.Generate(gen); err != nil {
+		return nil, err
+	}
+
+	resp := gen.Response()
+	if resp.Error != nil {
+		return nil, errors.New(resp.GetError())
+	}
+	return resp, nil
+}
+
+// relativeErrorsOptions returns the options linking the shared errors document relative to the document.
+func (d *document) relativeErrorsOptions(opts *doc.Options) *doc.Options {
+	o := *opts
+	if rel, err := filepath.Rel(filepath.Dir(d.prefix), opts.ErrorsURL); err == nil {
+		o.ErrorsURL = filepath.ToSlash(rel)
+	}
+	return &o
+}
+
+// documents groups the services of the generated files according to the layout.
+func documents(plugin *protogen.Plugin, layout string, opts *doc.Options) ([]*document, error) {
+	switch layout {
+	case layoutService, layoutPackage, layoutSingle:
+	default:
+		return nil, fmt.Errorf("unknown layout %q", layout)
+	}
+
+	var docs []*document
+	packages := make(map[string]*document)
+
+	for _, file := range plugin.Files {
+		if !file.Generate {
+			continue
+		}
+
+		services := make([]*protogen.Service, 0, len(file.Services))
+		for _, service := range file.Services {
+			if !opts.Hidden(service.Desc) {
+				services = append(services, service)
+			}
+		}
+		if len(services) == 0 {
+			continue
+		}
+
+		switch layout {
+		case layoutService:
+			for _, service := range services {
+				docs = append(docs, &document{
+					source:     file.Desc.Path(),
+					prefix:     filepath.Join(filepath.Dir(file.GeneratedFilenamePrefix), string(service.Desc.Name())),
+					title:      string(service.Desc.Name()),
+					services:   []*protogen.Service{service},
+					importPath: file.GoImportPath,
+				})
+			}
+
+		case layoutPackage:
+			pkg := string(file.Desc.Package())
+			d, ok := packages[pkg]
+			if !ok {
+				d = &document{
+					source:     pkg,
+					prefix:     filepath.Join(filepath.Dir(file.GeneratedFilenamePrefix), pkg),
+					title:      pkg,
+					importPath: file.GoImportPath,
+					combined:   true,
+				}
+				packages[pkg] = d
+				docs = append(docs, d)
+			}
+			d.services = append(d.services, services...)
+
+		case layoutSingle:
+			if len(docs) == 0 {
+				docs = append(docs, &document{
+					source:     singleDocumentName,
+					prefix:     singleDocumentName,
+					title:      singleDocumentTitle,
+					importPath: file.GoImportPath,
+					combined:   true,
+				})
+			}
+			docs[0].services = append(docs[0].services, services...)
+		}
+	}
+
+	return docs, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package plugin
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+
+	"github.com/albenik/twirp-doc-gen/twirpdoc"
+)
+
+// wellKnownPrefix is the path prefix of the well-known protobuf files.
+const wellKnownPrefix = "google/protobuf/"
+
+// ParseDescriptorSet parses the FileDescriptorSet encoded in the protobuf binary or the JSON format.
+func ParseDescriptorSet(data []byte) (*descriptorpb.FileDescriptorSet, error) {
+	set := new(descriptorpb.FileDescriptorSet)
+
+	var err error
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		err = protojson.Unmarshal(trimmed, set)
+	} else {
+		err = proto.Unmarshal(data, set)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid FileDescriptorSet: %w", err)
+	}
+
+	return set, nil
+}
+
+// NewRequest returns the plugin request generating the files of the descriptor set.
+// If no files are given, all the files of the set are generated except the well-known and the twirp_doc ones.
+// Dependencies missing from the set are resolved from the well-known files linked into the binary.
+// The files without the go_package option get the placeholder import path of their directory,
+// the explicit M parameters take precedence.
+func NewRequest(set *descriptorpb.FileDescriptorSet, files []string, parameter string) (*pluginpb.CodeGeneratorRequest, error) {
+	req := &pluginpb.CodeGeneratorRequest{
+		Parameter: proto.String(importPathParameter(set, parameter)),
+	}
+
+	known := make(map[string]bool, len(set.File))
+	for _, f := range set.File {
+		known[f.GetName()] = true
+	}
+
+	added := make(map[string]bool, len(set.File))
+
+	var addDependency func(path string) error
+	addDependency = func(path string) error {
+		if known[path] || added[path] {
+			return nil
+		}
+		fd, err := protoregistry.GlobalFiles.FindFileByPath(path)
+		if err != nil {
+			return fmt.Errorf("dependency %s not found in the descriptor set", path)
+		}
+		added[path] = true
+		for i := 0; i < fd.Imports().Len(); i++ {
+			if err := addDependency(fd.Imports().Get(i).Path()); err != nil {
+				return err
+			}
+		}
+		req.ProtoFile = append(req.ProtoFile, protodesc.ToFileDescriptorProto(fd))
+		return nil
+	}
+
+	for _, f := range set.File {
+		for _, dep := range f.Dependency {
+			if err := addDependency(dep); err != nil {
+				return nil, fmt.Errorf("%s: %w", f.GetName(), err)
+			}
+		}
+		req.ProtoFile = append(req.ProtoFile, f)
+	}
+
+	if len(files) == 0 {
+		for _, f := range set.File {
+			if name := f.GetName(); !strings.HasPrefix(name, wellKnownPrefix) &&
+				name != twirpdoc.File_twirpdoc_twirp_doc_proto.Path() {
+				req.FileToGenerate = append(req.FileToGenerate, name)
+			}
+		}
+		return req, nil
+	}
+
+	for _, name := range files {
+		if !known[name] {
+			return nil, fmt.Errorf("%s: file not found in the descriptor set", name)
+		}
+		req.FileToGenerate = append(req.FileToGenerate, name)
+	}
+
+	return req, nil
+}
+
+// importPathParameter returns the parameter prefixed with the M<file>=<import path> mappings of the files
+// declaring no go_package option, protogen can not determine their Go import paths otherwise.
+func importPathParameter(set *descriptorpb.FileDescriptorSet, parameter string) string {
+	params := make([]string, 0, len(set.File)+1)
+	for _, f := range set.File {
+		if f.GetOptions().GetGoPackage() != "" {
+			continue
+		}
+		name := f.GetName()
+		importPath := path.Dir(name)
+		if importPath == "." {
+			// protogen requires the import path to contain the slash or the period
+			importPath = "./" + strings.TrimSuffix(name, path.Ext(name))
+		}
+		params = append(params, "M"+name+"="+importPath)
+	}
+	if parameter != "" {
+		params = append(params, parameter)
+	}
+	return strings.Join(params, ",")
+}
@@ -0,0 +1,179 @@
+package plugin_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/albenik/twirp-doc-gen/internal/plugin"
+)
+
+const testDescriptorSet = `
+file {
+  name: "test/v1/user.proto"
+  package: "test.v1"
+  dependency: "google/protobuf/empty.proto"
+  syntax: "proto3"
+  options { go_package: "example.com/test/v1;testv1" }
+
+  message_type {
+    name: "User"
+    field { name: "id" json_name: "id" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING }
+  }
+
+  service {
+    name: "UserService"
+    method { name: "Get" input_type: ".google.protobuf.Empty" output_type: ".test.v1.User" }
+  }
+}
+`
+
+func testSet(t *testing.T) *descriptorpb.FileDescriptorSet {
+	t.Helper()
+
+	set := new(descriptorpb.FileDescriptorSet)
+	require.NoError(t, prototext.Unmarshal([]byte(testDescriptorSet), set))
+	return set
+}
+
+func TestParseDescriptorSet(t *testing.T) {
+	t.Parallel()
+
+	set := testSet(t)
+
+	bin, err := proto.Marshal(set)
+	require.NoError(t, err)
+	parsed, err := plugin.ParseDescriptorSet(bin)
+	require.NoError(t, err)
+	require.True(t, proto.Equal(set, parsed))
+
+	js, err := protojson.Marshal(set)
+	require.NoError(t, err)
+	parsed, err = plugin.ParseDescriptorSet(append([]byte("\n  "), js...))
+	require.NoError(t, err)
+	require.True(t, proto.Equal(set, parsed))
+
+	_, err = plugin.ParseDescriptorSet([]byte("{\"file\": 1}"))
+	require.Error(t, err)
+}
+
+func TestNewRequest(t *testing.T) {
+	t.Parallel()
+
+	set := testSet(t)
+
+	req, err := plugin.NewRequest(set, nil, "layout=single")
+	require.NoError(t, err)
+	require.Equal(t, "layout=single", req.GetParameter())
+	require.Equal(t, []string{"test/v1/user.proto"}, req.FileToGenerate)
+	require.Len(t, req.ProtoFile, 2)
+	require.Equal(t, "google/protobuf/empty.proto", req.ProtoFile[0].GetName())
+
+	_, err = plugin.NewRequest(set, []string{"test/v1/unknown.proto"}, "")
+	require.EqualError(t, err, "test/v1/unknown.proto: file not found in the descriptor set")
+
+	set.File[0].Dependency = append(set.File[0].Dependency, "test/v1/missing.proto")
+	_, err = plugin.NewRequest(set, nil, "")
+	require.EqualError(t, err, "test/v1/user.proto: dependency test/v1/missing.proto not found in the descriptor set")
+}
+
+func TestNewRequest_NoGoPackage(t *testing.T) {
+	t.Parallel()
+
+	set := testSet(t)
+	set.File[0].Options = nil
+	set.File = append(set.File, &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("status.proto"),
+		Package: proto.String("status"),
+		Syntax:  proto.String("proto3"),
+	})
+
+	req, err := plugin.NewRequest(set, nil, "paths=source_relative")
+	require.NoError(t, err)
+	require.Equal(t, "Mtest/v1/user.proto=test/v1,Mstatus.proto=./status,paths=source_relative", req.GetParameter())
+
+	resp, err := plugin.New().Execute(req)
+	require.NoError(t, err)
+	require.Empty(t, resp.GetError())
+	require.Len(t, resp.File, 1)
+	require.Equal(t, "test/v1/UserService.md", resp.File[0].GetName())
+
+	req, err = plugin.NewRequest(set, nil, "Mtest/v1/user.proto=example.com/test/v1")
+	require.NoError(t, err)
+
+	resp, err = plugin.New().Execute(req)
+	require.NoError(t, err)
+	require.Equal(t, "example.com/test/v1/UserService.md", resp.File[0].GetName())
+}
+
+func TestPlugin_Execute(t *testing.T) {
+	t.Parallel()
+
+	req, err := plugin.NewRequest(testSet(t), nil, "paths=source_relative,format=markdown+openapi+json,errors=link")
+	require.NoError(t, err)
+
+	resp, err := plugin.New().Execute(req)
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(resp.File))
+	for _, f := range resp.File {
+		names = append(names, f.GetName())
+	}
+	require.Equal(t, []string{
+		"test/v1/UserService.md",
+		"test/v1/UserService.openapi.yaml",
+		"test/v1/UserService.json",
+		"twirp_errors.md",
+	}, names)
+	require.Contains(t, resp.File[0].GetContent(), "See [Twirp error codes](../../twirp_errors.md).")
+	require.Contains(t, resp.File[2].GetContent(), `"url": "https://api.example.com/twirp/test.v1.UserService/Get"`)
+
+	req.Parameter = proto.String("layout=unknown")
+	_, err = plugin.New().Execute(req)
+	require.EqualError(t, err, `unknown layout "unknown"`)
+}
+
+func TestPlugin_ExecuteHTML(t *testing.T) {
+	t.Parallel()
+
+	req, err := plugin.NewRequest(testSet(t), nil, "paths=source_relative,format=html")
+	require.NoError(t, err)
+
+	resp, err := plugin.New().Execute(req)
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(resp.File))
+	for _, f := range resp.File {
+		names = append(names, f.GetName())
+	}
+	require.Equal(t, []string{"index.html", "test/v1/UserService.html"}, names)
+	require.Contains(t, resp.File[0].GetContent(), `<a href="test/v1/UserService.html#post-get">Get</a>`)
+	require.Contains(t, resp.File[1].GetContent(), `<h1 id="userservice">`)
+}
+
+func TestPlugin_ExecuteTemplate(t *testing.T) {
+	t.Parallel()
+
+	tmpl := filepath.Join(t.TempDir(), "service.md.tmpl")
+	require.NoError(t, os.WriteFile(tmpl, []byte("# {{ .Service.Name }}\n\n{{ fieldsTable (index .Service.Methods 0).Response }}\n"), 0o600))
+
+	req, err := plugin.NewRequest(testSet(t), nil, "paths=source_relative,template="+tmpl)
+	require.NoError(t, err)
+
+	resp, err := plugin.New().Execute(req)
+	require.NoError(t, err)
+	require.Len(t, resp.File, 1)
+	require.Equal(t, "# UserService\n\n| Field |  Type  | Description |\n|:------|:------:|:------------|\n| `id`  | string |             |\n",
+		resp.File[0].GetContent())
+
+	req.Parameter = proto.String("template=" + tmpl + ".missing")
+	_, err = plugin.New().Execute(req)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "template: open ")
+}
@@ -0,0 +1,65 @@
+package site
+
+import (
+	"html"
+	"strings"
+)
+
+// highlightJSON returns the escaped JSON code with the keys, strings, numbers and literals wrapped in the spans
+// styled by the site CSS. Invalid JSON is highlighted as far as possible.
+func highlightJSON(code string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(code); {
+		c := code[i]
+
+		switch {
+		case c == '"':
+			j := i + 1
+			for j < len(code) && code[j] != '"' {
+				if code[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j < len(code) {
+				j++
+			}
+
+			class := "s"
+			if k := strings.TrimLeft(code[j:], " \t\r\n"); strings.HasPrefix(k, ":") {
+				class = "k"
+			}
+			span(&b, class, code[i:j])
+			i = j
+
+		case c == '-' || c >= '0' && c <= '9':
+			j := i + 1
+			for j < len(code) && strings.IndexByte("0123456789+-.eE", code[j]) >= 0 {
+				j++
+			}
+			span(&b, "n", code[i:j])
+			i = j
+
+		case strings.HasPrefix(code[i:], "true"), strings.HasPrefix(code[i:], "null"):
+			span(&b, "l", code[i:i+4])
+			i += 4
+
+		case strings.HasPrefix(code[i:], "false"):
+			span(&b, "l", code[i:i+5])
+			i += 5
+
+		default:
+			b.WriteString(html.EscapeString(code[i : i+1]))
+			i++
+		}
+	}
+
+	return b.String()
+}
+
+func span(b *strings.Builder, class, text string) {
+	b.WriteString(`<span class="` + class + `">`)
+	b.WriteString(html.EscapeString(text))
+	b.WriteString("</span>")
+}
@@ -0,0 +1,210 @@
+package site
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"regexp"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+
+	md "github.com/albenik/twirp-doc-gen/internal/markdown"
+)
+
+var (
+	htmlTagRx     = regexp.MustCompile(`<[^>]*>`)
+	allowedHTMLRx = regexp.MustCompile(`^</?(details|summary|br)\s*/?>$`)
+)
+
+// heading is the heading of the converted document.
+type heading struct {
+	Level int
+	ID    string
+	HTML  string
+}
+
+// converter is the goldmark AST transformer and renderer converting the generated documents to HTML.
+// It rewrites the links to the Markdown documents, drops the links with unsafe URL schemes, renders the headings
+// with their anchors, highlights the JSON code and passes through only the raw HTML the generator produces.
+type converter struct {
+	out      bytes.Buffer
+	headings []*heading
+	anchors  md.Anchors
+	start    int
+}
+
+// convert returns the HTML of the Markdown document and its headings.
+func convert(markdown string) (string, []*heading, error) {
+	c := &converter{anchors: make(md.Anchors)}
+
+	gm := goldmark.New(
+		goldmark.WithExtensions(
+			extension.NewTable(extension.WithTableCellAlignMethod(extension.TableCellAlignStyle)),
+			extension.Strikethrough,
+		),
+		goldmark.WithParserOptions(parser.WithASTTransformers(util.Prioritized(c, 0))),
+		goldmark.WithRendererOptions(renderer.WithNodeRenderers(util.Prioritized(c, 0))),
+	)
+	if err := gm.Convert([]byte(markdown), &c.out); err != nil {
+		return "", nil, err
+	}
+	return c.out.String(), c.headings, nil
+}
+
+// Transform implements parser.ASTTransformer.
+func (c *converter) Transform(doc *ast.Document, reader text.Reader, _ parser.Context) {
+	source := reader.Source()
+
+	// the nodes are replaced after the walk to keep the walk consistent
+	var links []ast.Node
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering {
+			switch n.Kind() {
+			case ast.KindLink, ast.KindImage, ast.KindAutoLink:
+				links = append(links, n)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, n := range links {
+		switch n := n.(type) {
+		case *ast.Link:
+			if href, ok := pageHref(string(n.Destination)); ok {
+				n.Destination = []byte(href)
+				continue
+			}
+			for child := n.FirstChild(); child != nil; child = n.FirstChild() {
+				n.Parent().InsertBefore(n.Parent(), n, child)
+			}
+			n.Parent().RemoveChild(n.Parent(), n)
+		case *ast.Image:
+			if href, ok := pageHref(string(n.Destination)); ok {
+				n.Destination = []byte(href)
+				continue
+			}
+			n.Parent().ReplaceChild(n.Parent(), n, ast.NewString(n.Text(source)))
+		case *ast.AutoLink:
+			if _, ok := pageHref(string(n.URL(source))); !ok {
+				n.Parent().ReplaceChild(n.Parent(), n, ast.NewString(n.Label(source)))
+			}
+		}
+	}
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.
+func (c *converter) RegisterFuncs(r renderer.NodeRendererFuncRegisterer) {
+	r.Register(ast.KindHeading, c.renderHeading)
+	r.Register(ast.KindCodeBlock, c.renderCodeBlock)
+	r.Register(ast.KindFencedCodeBlock, c.renderCodeBlock)
+	r.Register(ast.KindHTMLBlock, c.renderHTMLBlock)
+	r.Register(ast.KindRawHTML, c.renderRawHTML)
+}
+
+// renderHeading renders the heading with the anchor link. The id is built from the heading text by md.Anchors,
+// the generator builds the links to the headings the same way.
+func (c *converter) renderHeading(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*ast.Heading)
+
+	if entering {
+		id := c.anchors.Anchor(string(n.Text(source)))[1:]
+		c.headings = append(c.headings, &heading{Level: n.Level, ID: id})
+
+		fmt.Fprintf(w, "<h%d id=\"%s\"><a class=\"anchor\" href=\"#%s\">#</a>", n.Level, id, id)
+		if err := w.Flush(); err != nil {
+			return ast.WalkStop, err
+		}
+		c.start = c.out.Len()
+		return ast.WalkContinue, nil
+	}
+
+	if err := w.Flush(); err != nil {
+		return ast.WalkStop, err
+	}
+	c.headings[len(c.headings)-1].HTML = c.out.String()[c.start:]
+	fmt.Fprintf(w, "</h%d>\n", n.Level)
+	return ast.WalkContinue, nil
+}
+
+// renderCodeBlock renders the indented and fenced code blocks, the JSON code is highlighted.
+func (c *converter) renderCodeBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	var code bytes.Buffer
+	for i := 0; i < node.Lines().Len(); i++ {
+		line := node.Lines().At(i)
+		code.Write(line.Value(source))
+	}
+	body := html.EscapeString(string(bytes.TrimRight(code.Bytes(), "\n")))
+
+	var lang string
+	if n, ok := node.(*ast.FencedCodeBlock); ok {
+		lang = string(n.Language(source))
+	}
+
+	switch lang {
+	case "":
+		fmt.Fprintf(w, "<pre><code>%s</code></pre>\n", body)
+	case "json":
+		body = highlightJSON(string(bytes.TrimRight(code.Bytes(), "\n")))
+		fallthrough
+	default:
+		fmt.Fprintf(w, "<pre><code class=\"language-%s\">%s</code></pre>\n", html.EscapeString(lang), body)
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (c *converter) renderHTMLBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*ast.HTMLBlock)
+
+	var lines []byte
+	if entering {
+		for i := 0; i < n.Lines().Len(); i++ {
+			line := n.Lines().At(i)
+			lines = append(lines, line.Value(source)...)
+		}
+	} else if n.HasClosure() {
+		lines = n.ClosureLine.Value(source)
+	}
+
+	_, err := w.WriteString(safeHTML(string(lines)))
+	return ast.WalkContinue, err
+}
+
+func (c *converter) renderRawHTML(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkSkipChildren, nil
+	}
+
+	n := node.(*ast.RawHTML)
+	var raw []byte
+	for i := 0; i < n.Segments.Len(); i++ {
+		segment := n.Segments.At(i)
+		raw = append(raw, segment.Value(source)...)
+	}
+
+	_, err := w.WriteString(safeHTML(string(raw)))
+	return ast.WalkSkipChildren, err
+}
+
+// safeHTML returns the raw HTML as is if it contains only the details, summary and line break tags
+// without attributes, the tags the generator produces, otherwise it returns the escaped HTML.
+func safeHTML(raw string) string {
+	for _, tag := range htmlTagRx.FindAllString(raw, -1) {
+		if !allowedHTMLRx.MatchString(tag) {
+			return html.EscapeString(raw)
+		}
+	}
+	if bytes.ContainsAny([]byte(htmlTagRx.ReplaceAllString(raw, "")), "<>") {
+		return html.EscapeString(raw)
+	}
+	return raw
+}
@@ -0,0 +1,135 @@
+package site
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvert(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		Name     string
+		Markdown string
+		HTML     string
+	}{
+		{
+			Name:     "Headings",
+			Markdown: "# Title\n\n### POST `/Get`\n\n### Title\n",
+			HTML: `<h1 id="title"><a class="anchor" href="#title">#</a>Title</h1>` + "\n" +
+				`<h3 id="post-get"><a class="anchor" href="#post-get">#</a>POST <code>/Get</code></h3>` + "\n" +
+				`<h3 id="title-1"><a class="anchor" href="#title-1">#</a>Title</h3>` + "\n",
+		},
+		{
+			Name:     "Paragraphs",
+			Markdown: "first\nline\n\n---\n\n*Escaped \\*text\\* & <html>*\n",
+			HTML:     "<p>first\nline</p>\n<hr>\n<p><em>Escaped *text* &amp; &lt;html&gt;</em></p>\n",
+		},
+		{
+			Name:     "Inline",
+			Markdown: "**Deprecated:** ~~`old`~~ [the label](#label), [docs](other.md#a) <https://example.com><br/>`` a`b ``\n",
+			HTML: `<p><strong>Deprecated:</strong> <del><code>old</code></del> <a href="#label">the label</a>, ` +
+				`<a href="other.html#a">docs</a> <a href="https://example.com">https://example.com</a><br/>` +
+				"<code>a`b</code></p>\n",
+		},
+		{
+			Name:     "Lists",
+			Markdown: "* [Get](#get): first\n* second\n\n1. one\n2. two\n",
+			HTML:     "<ul>\n<li><a href=\"#get\">Get</a>: first</li>\n<li>second</li>\n</ul>\n<ol>\n<li>one</li>\n<li>two</li>\n</ol>\n",
+		},
+		{
+			Name:     "Quote",
+			Markdown: "> **Note:**\n>\n> text\n",
+			HTML:     "<blockquote>\n<p><strong>Note:</strong></p>\n<p>text</p>\n</blockquote>\n",
+		},
+		{
+			Name:     "Code",
+			Markdown: "````sh\n```\ncurl -d '{}'\n````\n\n    indented <code>\n",
+			HTML: "<pre><code class=\"language-sh\">```\ncurl -d &#39;{}&#39;</code></pre>\n" +
+				"<pre><code>indented &lt;code&gt;</code></pre>\n",
+		},
+		{
+			Name:     "JSON",
+			Markdown: "```json\n{\"id\": \"a\\\"b\", \"n\": -1.5e3, \"ok\": [true, null]}\n```\n",
+			HTML: `<pre><code class="language-json">{<span class="k">&#34;id&#34;</span>: <span class="s">&#34;a\&#34;b&#34;</span>, ` +
+				`<span class="k">&#34;n&#34;</span>: <span class="n">-1.5e3</span>, ` +
+				`<span class="k">&#34;ok&#34;</span>: [<span class="l">true</span>, <span class="l">null</span>]}</code></pre>` + "\n",
+		},
+		{
+			Name: "Table",
+			Markdown: "| Field | Type | Description |\n|:------|:----:|------------:|\n" +
+				"| `a\\|b` | string | Either a\\|b<br/>or c |\n",
+			HTML: "<table>\n<thead>\n<tr>\n" +
+				`<th style="text-align:left">Field</th>` + "\n" +
+				`<th style="text-align:center">Type</th>` + "\n" +
+				`<th style="text-align:right">Description</th>` + "\n" +
+				"</tr>\n</thead>\n<tbody>\n<tr>\n" +
+				`<td style="text-align:left"><code>a|b</code></td>` + "\n" +
+				`<td style="text-align:center">string</td>` + "\n" +
+				`<td style="text-align:right">Either a|b<br/>or c</td>` + "\n" +
+				"</tr>\n</tbody>\n</table>\n",
+		},
+		{
+			Name:     "Underscores",
+			Markdown: "_em_ __strong__ snake_case_name\n",
+			HTML:     "<p><em>em</em> <strong>strong</strong> snake_case_name</p>\n",
+		},
+		{
+			Name:     "NestedLists",
+			Markdown: "* parent\n  * child\n* next\n",
+			HTML:     "<ul>\n<li>parent\n<ul>\n<li>child</li>\n</ul>\n</li>\n<li>next</li>\n</ul>\n",
+		},
+		{
+			Name:     "ReferenceLinks",
+			Markdown: "See [the docs][docs].\n\n[docs]: other.md#a\n",
+			HTML:     "<p>See <a href=\"other.html#a\">the docs</a>.</p>\n",
+		},
+		{
+			Name:     "SetextHeadings",
+			Markdown: "Title\n=====\n\nSection\n-------\n",
+			HTML: `<h1 id="title"><a class="anchor" href="#title">#</a>Title</h1>` + "\n" +
+				`<h2 id="section"><a class="anchor" href="#section">#</a>Section</h2>` + "\n",
+		},
+		{
+			Name:     "HeadingWithLink",
+			Markdown: "## The [User](https://example.com/user) model\n",
+			HTML: `<h2 id="the-user-model"><a class="anchor" href="#the-user-model">#</a>` +
+				`The <a href="https://example.com/user">User</a> model</h2>` + "\n",
+		},
+		{
+			Name:     "Image",
+			Markdown: "![img](x.png)\n",
+			HTML:     "<p><img src=\"x.png\" alt=\"img\"></p>\n",
+		},
+		{
+			Name: "UnsafeLinks",
+			Markdown: "[js](javascript:alert(1)) [data](data:text/html,x) [tab](<java\tscript:x>) " +
+				"[entity](&#106;avascript:x) [escape](javascript\\:x) " +
+				"![img](data:image/png;base64,x) <vbscript:x> [ok](mailto:a@example.com)\n",
+			HTML: "<p>js data tab entity escape img vbscript:x <a href=\"mailto:a@example.com\">ok</a></p>\n",
+		},
+		{
+			Name:     "UnsafeHTML",
+			Markdown: "<script>alert(1)</script>\n\ntext <b onclick=\"x\">b</b><br>\n",
+			HTML:     "&lt;script&gt;alert(1)&lt;/script&gt;\n<p>text &lt;b onclick=&#34;x&#34;&gt;b&lt;/b&gt;<br></p>\n",
+		},
+		{
+			Name:     "Details",
+			Markdown: "<details>\n<summary>Twirp error codes</summary>\n\ntext\n\n</details>\n",
+			HTML:     "<details>\n<summary>Twirp error codes</summary>\n<p>text</p>\n</details>\n",
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+
+			html, _, err := convert(c.Markdown)
+			require.NoError(t, err)
+			require.Equal(t, c.HTML, html)
+		})
+	}
+}
@@ -0,0 +1,176 @@
+// Package site renders the generated Markdown documents as the pages of the self-contained static HTML site.
+package site
+
+import (
+	_ "embed" // the site CSS
+	"html"
+	"html/template"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+)
+
+//go:embed style.css
+var style string
+
+var urlSchemeRx = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9+.\-]*):`)
+
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{ .Title }}</title>
+<style>
+{{ .Style }}</style>
+</head>
+<body>
+<nav>
+<a class="title" href="{{ .Home.Href }}">{{ .Home.Title }}</a>
+<ul>
+{{- range .Pages }}
+<li{{ if .Current }} class="current"{{ end }}><a href="{{ .Href }}">{{ .Title }}</a>
+{{- if .Current }}{{ template "outline" $.Outline }}{{ end }}</li>
+{{- end }}
+</ul>
+</nav>
+<main>
+{{ .Body }}</main>
+</body>
+</html>
+{{ define "outline" }}{{ if . }}
+<ul>
+{{- range . }}
+<li><a href="#{{ .ID }}">{{ .HTML }}</a>{{ template "outline" .Children }}</li>
+{{- end }}
+</ul>
+{{- end }}{{ end }}`))
+
+// Page is the page of the site generated from the Markdown document.
+type Page struct {
+	// Path is the slash separated path of the page relative to the site root.
+	Path     string
+	Title    string
+	Markdown string
+}
+
+type pageLink struct {
+	Title   string
+	Href    string
+	Current bool
+}
+
+type outlineItem struct {
+	ID       string
+	HTML     template.HTML
+	Children []*outlineItem
+}
+
+type pageData struct {
+	Title   string
+	Style   template.CSS
+	Home    *pageLink
+	Pages   []*pageLink
+	Outline []*outlineItem
+	Body    template.HTML
+}
+
+// PagePath returns the path of the page generated from the Markdown document.
+func PagePath(document string) string {
+	return strings.TrimSuffix(document, ".md") + ".html"
+}
+
+// Write writes the page with the sidebar linking the pages of the site, the first page is the home page.
+// The links to the Markdown documents are replaced with the links to their pages.
+func Write(w io.Writer, page *Page, pages []*Page) error {
+	body, headings, err := convert(page.Markdown)
+	if err != nil {
+		return err
+	}
+
+	data := &pageData{
+		Title:   page.Title,
+		Style:   template.CSS(style), //nolint:gosec
+		Outline: outline(headings),
+		Body:    template.HTML(body), //nolint:gosec
+	}
+
+	for _, p := range pages {
+		link := &pageLink{
+			Title:   p.Title,
+			Href:    relativeHref(page.Path, p.Path),
+			Current: p == page,
+		}
+		if data.Home == nil {
+			data.Home = link
+			continue
+		}
+		data.Pages = append(data.Pages, link)
+	}
+	if data.Home == nil {
+		data.Home = &pageLink{Title: page.Title, Href: path.Base(page.Path), Current: true}
+	}
+
+	return pageTemplate.Execute(w, data)
+}
+
+// outline returns the second and third level headings, the headings preceding the first second level one
+// belong to the table of contents and are skipped.
+func outline(headings []*heading) []*outlineItem {
+	var items []*outlineItem
+	for _, h := range headings {
+		switch {
+		case h.Level == 2: //nolint:gomnd
+			items = append(items, &outlineItem{ID: h.ID, HTML: template.HTML(h.HTML)}) //nolint:gosec
+		case h.Level == 3 && len(items) > 0: //nolint:gomnd
+			parent := items[len(items)-1]
+			parent.Children = append(parent.Children, &outlineItem{ID: h.ID, HTML: template.HTML(h.HTML)}) //nolint:gosec
+		}
+	}
+	return items
+}
+
+// relativeHref returns the link to the page relative to the directory of the current page.
+func relativeHref(from, to string) string {
+	dir := path.Dir(from)
+	if dir == "." {
+		return to
+	}
+
+	up := strings.Repeat("../", strings.Count(dir, "/")+1)
+	return up + to
+}
+
+// pageHref returns the link to the page of the relative link to the Markdown document and reports
+// whether the link is safe. Only the relative links and the http, https and mailto schemes are safe.
+// The scheme is checked with the character references and the backslash escapes resolved.
+func pageHref(href string) (string, bool) {
+	if m := urlSchemeRx.FindStringSubmatch(strings.Map(schemeRune, html.UnescapeString(href))); m != nil {
+		switch strings.ToLower(m[1]) {
+		case "http", "https", "mailto":
+			return href, true
+		}
+		return "", false
+	}
+	if strings.HasPrefix(href, "#") {
+		return href, true
+	}
+
+	target, fragment := href, ""
+	if i := strings.IndexByte(href, '#'); i >= 0 {
+		target, fragment = href[:i], href[i:]
+	}
+	if strings.HasSuffix(target, ".md") {
+		target = PagePath(target)
+	}
+	return target + fragment, true
+}
+
+// schemeRune drops the backslashes, the control characters and the spaces browsers ignore in the URL scheme.
+func schemeRune(r rune) rune {
+	if r <= ' ' || r == '\\' {
+		return -1
+	}
+	return r
+}
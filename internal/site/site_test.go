@@ -0,0 +1,74 @@
+package site_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/albenik/twirp-doc-gen/internal/site"
+)
+
+func TestPagePath(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "test/v1/UserService.html", site.PagePath("test/v1/UserService.md"))
+	require.Equal(t, "index.html", site.PagePath("index.md"))
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	index := &site.Page{
+		Path:     "index.html",
+		Title:    "API Reference",
+		Markdown: "# API Reference\n\n* [Get](test/v1/UserService.md#post-get)\n",
+	}
+	service := &site.Page{
+		Path:  "test/v1/UserService.html",
+		Title: "UserService",
+		Markdown: "# UserService\n\n### Methods\n\n* [Get](#post-get)\n\n---\n\n" +
+			"## Methods\n\n### POST `/Get`\n\n#### Request\n\n## Models\n\n### test.v1.User\n\n" +
+			"## Twirp Errors\n\nSee [Twirp error codes](../../twirp_errors.md).\n",
+	}
+	errors := &site.Page{
+		Path:     "twirp_errors.html",
+		Title:    "Twirp Errors",
+		Markdown: "# Twirp Errors\n",
+	}
+	pages := []*site.Page{index, service, errors}
+
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, site.Write(buf, service, pages))
+	out := buf.String()
+
+	require.Contains(t, out, "<title>UserService</title>")
+	require.Contains(t, out, "<style>\n:root {")
+	require.NotContains(t, out, "<link")
+	require.NotContains(t, out, "<script")
+
+	require.Contains(t, out, "<nav>\n"+
+		`<a class="title" href="../../index.html">API Reference</a>`+"\n"+
+		"<ul>\n"+
+		`<li class="current"><a href="../../test/v1/UserService.html">UserService</a>`+"\n"+
+		"<ul>\n"+
+		`<li><a href="#methods-1">Methods</a>`+"\n"+
+		"<ul>\n"+
+		`<li><a href="#post-get">POST <code>/Get</code></a></li>`+"\n"+
+		"</ul></li>\n"+
+		`<li><a href="#models">Models</a>`+"\n"+
+		"<ul>\n"+
+		`<li><a href="#testv1user">test.v1.User</a></li>`+"\n"+
+		"</ul></li>\n"+
+		`<li><a href="#twirp-errors">Twirp Errors</a></li>`+"\n"+
+		"</ul></li>\n"+
+		`<li><a href="../../twirp_errors.html">Twirp Errors</a></li>`+"\n"+
+		"</ul>\n"+
+		"</nav>\n")
+	require.Contains(t, out, `See <a href="../../twirp_errors.html">Twirp error codes</a>.`)
+
+	buf.Reset()
+	require.NoError(t, site.Write(buf, index, pages))
+	require.Contains(t, buf.String(), `<a class="title" href="index.html">API Reference</a>`)
+	require.Contains(t, buf.String(), `<li><a href="test/v1/UserService.html#post-get">Get</a></li>`)
+}
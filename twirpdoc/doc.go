@@ -0,0 +1,4 @@
+// Package twirpdoc contains the protobuf options recognized by the documentation generator.
+package twirpdoc
+
+//go:generate protoc -I .. --go_out=.. --go_opt=paths=source_relative twirpdoc/twirp_doc.proto
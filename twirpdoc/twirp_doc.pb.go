@@ -0,0 +1,258 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        (unknown)
+// source: twirpdoc/twirp_doc.proto
+
+package twirpdoc
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	reflect "reflect"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+var file_twirpdoc_twirp_doc_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*string)(nil),
+		Field:         50701,
+		Name:          "twirp_doc.example",
+		Tag:           "bytes,50701,opt,name=example",
+		Filename:      "twirpdoc/twirp_doc.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         50704,
+		Name:          "twirp_doc.internal_field",
+		Tag:           "varint,50704,opt,name=internal_field",
+		Filename:      "twirpdoc/twirp_doc.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.MessageOptions)(nil),
+		ExtensionType: ([]string)(nil),
+		Field:         50702,
+		Name:          "twirp_doc.examples",
+		Tag:           "bytes,50702,rep,name=examples",
+		Filename:      "twirpdoc/twirp_doc.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.EnumValueOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         50703,
+		Name:          "twirp_doc.example_value",
+		Tag:           "varint,50703,opt,name=example_value",
+		Filename:      "twirpdoc/twirp_doc.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.EnumValueOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         50705,
+		Name:          "twirp_doc.internal_value",
+		Tag:           "varint,50705,opt,name=internal_value",
+		Filename:      "twirpdoc/twirp_doc.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         50706,
+		Name:          "twirp_doc.internal_method",
+		Tag:           "varint,50706,opt,name=internal_method",
+		Filename:      "twirpdoc/twirp_doc.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
+		ExtensionType: ([]string)(nil),
+		Field:         50708,
+		Name:          "twirp_doc.errors",
+		Tag:           "bytes,50708,rep,name=errors",
+		Filename:      "twirpdoc/twirp_doc.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
+		ExtensionType: ([]string)(nil),
+		Field:         50709,
+		Name:          "twirp_doc.error_meta",
+		Tag:           "bytes,50709,rep,name=error_meta",
+		Filename:      "twirpdoc/twirp_doc.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.ServiceOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         50707,
+		Name:          "twirp_doc.internal_service",
+		Tag:           "varint,50707,opt,name=internal_service",
+		Filename:      "twirpdoc/twirp_doc.proto",
+	},
+}
+
+// Extension fields to descriptorpb.FieldOptions.
+var (
+	// Example value of the field used in the generated JSON examples.
+	// The value is the JSON representation of the field, plain strings may be left unquoted.
+	//
+	// optional string example = 50701;
+	E_Example = &file_twirpdoc_twirp_doc_proto_extTypes[0]
+	// Omits the field from the documentation and the JSON examples.
+	//
+	// optional bool internal_field = 50704;
+	E_InternalField = &file_twirpdoc_twirp_doc_proto_extTypes[1]
+)
+
+// Extension fields to descriptorpb.MessageOptions.
+var (
+	// Complete JSON examples of the message.
+	// Each example is shown in the documentation instead of the generated one.
+	//
+	// repeated string examples = 50702;
+	E_Examples = &file_twirpdoc_twirp_doc_proto_extTypes[2]
+)
+
+// Extension fields to descriptorpb.EnumValueOptions.
+var (
+	// Marks the enum value to be used in the generated JSON examples.
+	// If several values are marked they are used in turn.
+	//
+	// optional bool example_value = 50703;
+	E_ExampleValue = &file_twirpdoc_twirp_doc_proto_extTypes[3]
+	// Omits the enum value from the documentation and the JSON examples.
+	//
+	// optional bool internal_value = 50705;
+	E_InternalValue = &file_twirpdoc_twirp_doc_proto_extTypes[4]
+)
+
+// Extension fields to descriptorpb.MethodOptions.
+var (
+	// Omits the method from the documentation.
+	//
+	// optional bool internal_method = 50706;
+	E_InternalMethod = &file_twirpdoc_twirp_doc_proto_extTypes[5]
+	// Twirp errors returned by the method. Each error is the Twirp error code and its meaning
+	// separated by the colon, e.g. "not_found: the user does not exist".
+	//
+	// repeated string errors = 50708;
+	E_Errors = &file_twirpdoc_twirp_doc_proto_extTypes[6]
+	// Meta keys of the Twirp errors returned by the method. Each key is prefixed by the Twirp error code
+	// and followed by its meaning, e.g. "invalid_argument.argument: the name of the invalid field".
+	//
+	// repeated string error_meta = 50709;
+	E_ErrorMeta = &file_twirpdoc_twirp_doc_proto_extTypes[7]
+)
+
+// Extension fields to descriptorpb.ServiceOptions.
+var (
+	// Omits the service from the documentation.
+	//
+	// optional bool internal_service = 50707;
+	E_InternalService = &file_twirpdoc_twirp_doc_proto_extTypes[8]
+)
+
+var File_twirpdoc_twirp_doc_proto protoreflect.FileDescriptor
+
+var file_twirpdoc_twirp_doc_proto_rawDesc = []byte{
+	0x0a, 0x18, 0x74, 0x77, 0x69, 0x72, 0x70, 0x64, 0x6f, 0x63, 0x2f, 0x74, 0x77, 0x69, 0x72, 0x70,
+	0x5f, 0x64, 0x6f, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09, 0x74, 0x77, 0x69, 0x72,
+	0x70, 0x5f, 0x64, 0x6f, 0x63, 0x1a, 0x20, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f,
+	0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x3a, 0x39, 0x0a, 0x07, 0x65, 0x78, 0x61, 0x6d, 0x70,
+	0x6c, 0x65, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x18, 0x8d, 0x8c, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x65, 0x78, 0x61, 0x6d, 0x70,
+	0x6c, 0x65, 0x3a, 0x46, 0x0a, 0x0e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x5f, 0x66,
+	0x69, 0x65, 0x6c, 0x64, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x18, 0x90, 0x8c, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x69, 0x6e, 0x74,
+	0x65, 0x72, 0x6e, 0x61, 0x6c, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x3a, 0x3d, 0x0a, 0x08, 0x65, 0x78,
+	0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x12, 0x1f, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x8e, 0x8c, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x08, 0x65, 0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x3a, 0x48, 0x0a, 0x0d, 0x65, 0x78, 0x61,
+	0x6d, 0x70, 0x6c, 0x65, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x21, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6e, 0x75,
+	0x6d, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x8f, 0x8c,
+	0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x65, 0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x56, 0x61,
+	0x6c, 0x75, 0x65, 0x3a, 0x4a, 0x0a, 0x0e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x5f,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x21, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6e, 0x75, 0x6d, 0x56, 0x61, 0x6c, 0x75,
+	0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x91, 0x8c, 0x03, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0d, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x49, 0x0a, 0x0f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x5f, 0x6d, 0x65, 0x74, 0x68,
+	0x6f, 0x64, 0x12, 0x1e, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0x92, 0x8c, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x69, 0x6e, 0x74, 0x65,
+	0x72, 0x6e, 0x61, 0x6c, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x3a, 0x38, 0x0a, 0x06, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x73, 0x12, 0x1e, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x4f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0x94, 0x8c, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x73, 0x3a, 0x3f, 0x0a, 0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x6d, 0x65,
+	0x74, 0x61, 0x12, 0x1e, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0x95, 0x8c, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x4d, 0x65, 0x74, 0x61, 0x3a, 0x4c, 0x0a, 0x10, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61,
+	0x6c, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x1f, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x93, 0x8c, 0x03, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x42, 0x2b, 0x5a, 0x29, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2f, 0x61, 0x6c, 0x62, 0x65, 0x6e, 0x69, 0x6b, 0x2f, 0x74, 0x77, 0x69, 0x72, 0x70, 0x2d,
+	0x64, 0x6f, 0x63, 0x2d, 0x67, 0x65, 0x6e, 0x2f, 0x74, 0x77, 0x69, 0x72, 0x70, 0x64, 0x6f, 0x63,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var file_twirpdoc_twirp_doc_proto_goTypes = []interface{}{
+	(*descriptorpb.FieldOptions)(nil),     // 0: google.protobuf.FieldOptions
+	(*descriptorpb.MessageOptions)(nil),   // 1: google.protobuf.MessageOptions
+	(*descriptorpb.EnumValueOptions)(nil), // 2: google.protobuf.EnumValueOptions
+	(*descriptorpb.MethodOptions)(nil),    // 3: google.protobuf.MethodOptions
+	(*descriptorpb.ServiceOptions)(nil),   // 4: google.protobuf.ServiceOptions
+}
+var file_twirpdoc_twirp_doc_proto_depIdxs = []int32{
+	0, // 0: twirp_doc.example:extendee -> google.protobuf.FieldOptions
+	0, // 1: twirp_doc.internal_field:extendee -> google.protobuf.FieldOptions
+	1, // 2: twirp_doc.examples:extendee -> google.protobuf.MessageOptions
+	2, // 3: twirp_doc.example_value:extendee -> google.protobuf.EnumValueOptions
+	2, // 4: twirp_doc.internal_value:extendee -> google.protobuf.EnumValueOptions
+	3, // 5: twirp_doc.internal_method:extendee -> google.protobuf.MethodOptions
+	3, // 6: twirp_doc.errors:extendee -> google.protobuf.MethodOptions
+	3, // 7: twirp_doc.error_meta:extendee -> google.protobuf.MethodOptions
+	4, // 8: twirp_doc.internal_service:extendee -> google.protobuf.ServiceOptions
+	9, // [9:9] is the sub-list for method output_type
+	9, // [9:9] is the sub-list for method input_type
+	9, // [9:9] is the sub-list for extension type_name
+	0, // [0:9] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_twirpdoc_twirp_doc_proto_init() }
+func file_twirpdoc_twirp_doc_proto_init() {
+	if File_twirpdoc_twirp_doc_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_twirpdoc_twirp_doc_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   0,
+			NumExtensions: 9,
+			NumServices:   0,
+		},
+		GoTypes:           file_twirpdoc_twirp_doc_proto_goTypes,
+		DependencyIndexes: file_twirpdoc_twirp_doc_proto_depIdxs,
+		ExtensionInfos:    file_twirpdoc_twirp_doc_proto_extTypes,
+	}.Build()
+	File_twirpdoc_twirp_doc_proto = out.File
+	file_twirpdoc_twirp_doc_proto_rawDesc = nil
+	file_twirpdoc_twirp_doc_proto_goTypes = nil
+	file_twirpdoc_twirp_doc_proto_depIdxs = nil
+}